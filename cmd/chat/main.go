@@ -1,28 +1,46 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/Petr09Mitin/xrust-beze-back/internal/repository/file_client"
 	study_material_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/study_material"
 	voice_recognition_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/voice_recognition"
+	authpb "github.com/Petr09Mitin/xrust-beze-back/proto/auth"
 	filepb "github.com/Petr09Mitin/xrust-beze-back/proto/file"
+	studymaterialpb "github.com/Petr09Mitin/xrust-beze-back/proto/study_material"
+	"time"
 
 	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/config"
+	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/grpcauth"
 	infrakafka "github.com/Petr09Mitin/xrust-beze-back/internal/pkg/kafka"
 	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/logger"
+	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/mongotx"
 	channelrepo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/channel"
 	message_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/chat"
+	outbox_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/outbox"
+	presence_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/presence"
+	ratelimit_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/ratelimit"
+	scheduled_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/scheduled"
 	structurization_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/structurization"
 	"github.com/Petr09Mitin/xrust-beze-back/internal/router/http/chat"
 	chat_service "github.com/Petr09Mitin/xrust-beze-back/internal/services/chat"
 	pb "github.com/Petr09Mitin/xrust-beze-back/proto/user"
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/olahol/melody"
+	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
+const (
+	// melody pings every 54s, so connection is refreshed in presence at least once per ttl
+	presenceTTL = 90 * time.Second
+)
+
 func main() {
 	log := logger.NewLogger()
 	cfg, err := config.NewChat()
@@ -36,8 +54,6 @@ func main() {
 		log.Fatal().Msg(fmt.Sprintf("failed to create kafka publisher: %v", err))
 		return
 	}
-	studyMaterialPub := study_material_repo.NewStudyMaterialPub(cfg.Kafka.StudyMaterialTopic, kafkaPub, log)
-	voiceRecognitionPub := voice_recognition_repo.NewVoiceRecognitionPubRepo(kafkaPub, cfg.Kafka.VoiceRecognitionNewVoiceTopic, log)
 	client, err := mongo.Connect(options.Client().ApplyURI(fmt.Sprintf(
 		"mongodb://%s:%s@%s:%d",
 		cfg.Mongo.Username,
@@ -51,12 +67,63 @@ func main() {
 	}
 	msgsCollection := client.Database(cfg.Mongo.Database).Collection("messages")
 	chanCollection := client.Database(cfg.Mongo.Database).Collection("channels")
+	readStatesCollection := client.Database(cfg.Mongo.Database).Collection("channel_read_states")
+	outboxCollection := client.Database(cfg.Mongo.Database).Collection("outbox")
+	structurizationsCollection := client.Database(cfg.Mongo.Database).Collection("structurizations")
+	scheduledCollection := client.Database(cfg.Mongo.Database).Collection("scheduled_messages")
+	scheduledRepo := scheduled_repo.NewScheduledMessageRepo(scheduledCollection, log)
+	err = scheduledRepo.EnsureIndexes(context.Background())
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to ensure scheduled messages indexes")
+		return
+	}
+	outboxRepo := outbox_repo.NewOutboxRepo(outboxCollection, log)
+	err = outboxRepo.EnsureIndexes(context.Background())
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to ensure outbox indexes")
+		return
+	}
+	// everything except ephemeral events goes to kafka through the outbox relay
+	outboxRelay := outbox_repo.NewOutboxRelay(outboxRepo, kafkaPub, cfg.Outbox, log)
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+	go outboxRelay.Run(relayCtx)
+	outboxPub := outbox_repo.NewOutboxPub(outboxRepo, outboxRelay, log)
+	txManager := mongotx.NewTxManager(client)
+	studyMaterialPub := study_material_repo.NewStudyMaterialPub(cfg.Kafka.StudyMaterialTopic, outboxPub, log)
+	voiceRecognitionPub := voice_recognition_repo.NewVoiceRecognitionPubRepo(outboxPub, cfg.Kafka.VoiceRecognitionNewVoiceTopic, log)
+	structurizationPub := structurization_repo.NewStructurizationPubRepo(outboxPub, cfg.Kafka.StructurizationRequestTopic, cfg.Kafka.SummaryRequestTopic, log)
+	structurizationCacheRepo := structurization_repo.NewStructurizationCacheRepo(structurizationsCollection, log)
 	msgRepo := message_repo.NewMessageRepo(msgsCollection, log)
-	msgPubRepo := message_repo.NewMessagePubRepo(kafkaPub, log)
+	err = msgRepo.EnsureIndexes(context.Background())
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to ensure messages indexes")
+		return
+	}
+	msgPubRepo := message_repo.NewMessagePubRepo(outboxPub, kafkaPub, log)
+	readStateRepo := message_repo.NewReadStateRepo(readStatesCollection, log)
+	err = readStateRepo.EnsureIndexes(context.Background())
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to ensure read states indexes")
+		return
+	}
 	chanRepo := channelrepo.NewChannelRepository(chanCollection, log)
+	err = chanRepo.EnsureIndexes(context.Background())
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to ensure channels indexes")
+		return
+	}
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port),
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})
+	presenceRepo := presence_repo.NewPresenceRepo(redisClient, presenceTTL, log)
+	rateLimitRepo := ratelimit_repo.NewRateLimitRepo(redisClient, log)
 	userGRPCConn, err := grpc.NewClient(
 		fmt.Sprintf("%s:%d", cfg.Services.UserService.Host, cfg.Services.UserService.Port),
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(grpcauth.ServiceTokenClientInterceptor(cfg.Services.UserService.Token)),
 	)
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to connect to user_service")
@@ -64,8 +131,6 @@ func main() {
 	}
 	userGRPCClient := pb.NewUserServiceClient(userGRPCConn)
 
-	structurizationRepo := structurization_repo.NewStructurizationRepository(cfg.Services.StructurizationService, log)
-
 	fileGRPCConn, err := grpc.NewClient(
 		fmt.Sprintf("%s:%d", cfg.Services.FileService.Host, cfg.Services.FileService.Port),
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
@@ -76,35 +141,51 @@ func main() {
 	}
 	fileGRPCClient := filepb.NewFileServiceClient(fileGRPCConn)
 	fileServiceClient := file_client.NewFileServiceClient(fileGRPCClient, log)
-	chatService := chat_service.NewChatService(msgRepo, msgPubRepo, chanRepo, fileServiceClient, structurizationRepo, userGRPCClient, studyMaterialPub, voiceRecognitionPub, log, cfg)
-	m := melody.New()
-	m.Config.MaxMessageSize = 1 << 20
-	msgsKafkaSub, err := infrakafka.NewKafkaSubscriber(cfg.Kafka)
-	if err != nil {
-		log.Err(err).Msg("failed to connect to kafka sub")
-		return
-	}
-	voiceRecognitionKafkaSub, err := infrakafka.NewKafkaSubscriber(cfg.Kafka)
-	if err != nil {
-		log.Err(err).Msg("failed to connect to kafka sub")
-		return
-	}
-	msgRouter, err := infrakafka.NewBrokerRouter()
+
+	studyMaterialGRPCConn, err := grpc.NewClient(
+		fmt.Sprintf("%s:%d", cfg.Services.StudyMaterialService.Host, cfg.Services.StudyMaterialService.Port),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
 	if err != nil {
-		log.Fatal().Err(err).Msg("failed to initialize kafka msg_router")
+		log.Fatal().Err(err).Msg("failed to connect to study_material")
 		return
 	}
-	msgSub, err := chat.NewMessageSubscriber(msgRouter, msgsKafkaSub, m, log)
+	studyMaterialGRPCClient := studymaterialpb.NewStudyMaterialServiceClient(studyMaterialGRPCConn)
+
+	authGRPCConn, err := grpc.NewClient(
+		fmt.Sprintf("%s:%d", cfg.Services.AuthService.Host, cfg.Services.AuthService.Port),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
 	if err != nil {
-		log.Fatal().Err(err).Msg("failed to connect to kafka msg_sub")
+		log.Fatal().Err(err).Msg("failed to connect to auth_service")
 		return
 	}
-	voiceRecognitionSub, err := chat.NewVoiceRecognitionSubscriber(cfg.Kafka.VoiceRecognitionVoiceProcessedTopic, msgRouter, voiceRecognitionKafkaSub, m, log)
+	authGRPCClient := authpb.NewAuthServiceClient(authGRPCConn)
+	chatService := chat_service.NewChatService(msgRepo, msgPubRepo, readStateRepo, chanRepo, presenceRepo, fileServiceClient, structurizationPub, structurizationCacheRepo, userGRPCClient, studyMaterialPub, studyMaterialGRPCClient, voiceRecognitionPub, scheduledRepo, txManager, log, cfg)
+	// unanswered and abandoned calls are finished with a history record, not only when the next call starts
+	sweepCtx, stopSweep := context.WithCancel(context.Background())
+	defer stopSweep()
+	go chatService.RunStaleCallsSweep(sweepCtx)
+	m := melody.New()
+	m.Config.MaxMessageSize = 1 << 20
+	m.Config.MessageBufferSize = chat.SessionMessageBufferSize
+	instanceID := watermill.NewShortUUID()
+	log.Info().Str("instance_id", instanceID).Msg("starting chat instance")
+	msgSub, voiceRecognitionSub, err := chat.NewInstanceSubscribers(
+		func(consumerGroup string) (message.Subscriber, error) {
+			return infrakafka.NewKafkaSubscriber(cfg.Kafka, consumerGroup)
+		},
+		cfg.Kafka.ConsumerGroup,
+		cfg.Kafka.VoiceRecognitionVoiceProcessedTopic,
+		instanceID,
+		m,
+		log,
+	)
 	if err != nil {
-		log.Fatal().Err(err).Msg("failed to connect to kafka voice_recognition_sub")
+		log.Fatal().Err(err).Msg("failed to init kafka subscribers")
 		return
 	}
-	c, err := chat.NewChat(chatService, msgSub, voiceRecognitionSub, m, log, cfg)
+	c, err := chat.NewChat(chatService, authGRPCClient, msgSub, voiceRecognitionSub, rateLimitRepo, m, log, cfg)
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to create chat")
 		return
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/grpcauth"
+	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/mongotx"
+	channelrepo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/channel"
+	message_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/chat"
+	outbox_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/outbox"
+	scheduled_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/scheduled"
+	"github.com/Petr09Mitin/xrust-beze-back/internal/router/daemons/schedulerd"
+	"github.com/Petr09Mitin/xrust-beze-back/internal/services/scheduler"
+	pb "github.com/Petr09Mitin/xrust-beze-back/proto/user"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/config"
+	infrakafka "github.com/Petr09Mitin/xrust-beze-back/internal/pkg/kafka"
+	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/logger"
+)
+
+const (
+	defaultPollInterval = time.Second
+	defaultBatchSize    = 100
+	defaultMaxAttempts  = 5
+)
+
+func main() {
+	// init logger
+	log := logger.NewLogger()
+	log.Println("Starting schedulerd...")
+
+	// init cfg
+	cfg, err := config.NewSchedulerD()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load schedulerd config")
+	}
+	pollInterval, batchSize, maxAttempts := defaultPollInterval, defaultBatchSize, defaultMaxAttempts
+	if cfg.Scheduler != nil {
+		if cfg.Scheduler.PollIntervalMs > 0 {
+			pollInterval = time.Duration(cfg.Scheduler.PollIntervalMs) * time.Millisecond
+		}
+		if cfg.Scheduler.BatchSize > 0 {
+			batchSize = cfg.Scheduler.BatchSize
+		}
+		if cfg.Scheduler.MaxAttempts > 0 {
+			maxAttempts = cfg.Scheduler.MaxAttempts
+		}
+	}
+
+	// init mongo
+	client, err := mongo.Connect(options.Client().ApplyURI(fmt.Sprintf(
+		"mongodb://%s:%s@%s:%d",
+		cfg.Mongo.Username,
+		cfg.Mongo.Password,
+		cfg.Mongo.Host,
+		cfg.Mongo.Port,
+	)))
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to connect to mongodb")
+		return
+	}
+	db := client.Database(cfg.Mongo.Database)
+	scheduledRepo := scheduled_repo.NewScheduledMessageRepo(db.Collection("scheduled_messages"), log)
+	err = scheduledRepo.EnsureIndexes(context.Background())
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to ensure scheduled messages indexes")
+		return
+	}
+	msgRepo := message_repo.NewMessageRepo(db.Collection("messages"), log)
+	channelRepo := channelrepo.NewChannelRepository(db.Collection("channels"), log)
+	outboxRepo := outbox_repo.NewOutboxRepo(db.Collection("outbox"), log)
+	txManager := mongotx.NewTxManager(client)
+
+	// blocks are checked again when the message is posted, they could change since it was scheduled
+	userGRPCConn, err := grpc.NewClient(
+		fmt.Sprintf("%s:%d", cfg.Services.UserService.Host, cfg.Services.UserService.Port),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(grpcauth.ServiceTokenClientInterceptor(cfg.Services.UserService.Token)),
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to connect to user_service")
+		return
+	}
+	defer userGRPCConn.Close()
+	userGRPCClient := pb.NewUserServiceClient(userGRPCConn)
+
+	// init kafka pub
+	kafkaPub, err := infrakafka.NewKafkaPublisher(cfg.Kafka)
+	if err != nil {
+		log.Err(err).Msg("failed to connect to kafka pub")
+		return
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	// posted messages are published to the message topic through the outbox, as in chat
+	outboxRelay := outbox_repo.NewOutboxRelay(outboxRepo, kafkaPub, cfg.Outbox, log)
+	go outboxRelay.Run(ctx)
+	outboxPub := outbox_repo.NewOutboxPub(outboxRepo, outboxRelay, log)
+	msgPubRepo := message_repo.NewMessagePubRepo(outboxPub, kafkaPub, log)
+
+	schedulerService := scheduler.NewSchedulerService(scheduledRepo, msgRepo, msgPubRepo, channelRepo, userGRPCClient, txManager, maxAttempts, log)
+	d := schedulerd.NewSchedulerD(schedulerService, pollInterval, batchSize, log)
+	if err = d.Run(ctx); err != nil {
+		log.Fatal().Err(err).Msg("error running schedulerd")
+		return
+	}
+}
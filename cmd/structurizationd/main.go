@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	channelrepo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/channel"
+	message_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/chat"
+	structurization_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/structurization"
+	"github.com/Petr09Mitin/xrust-beze-back/internal/router/daemons/structurizationd"
+	"github.com/Petr09Mitin/xrust-beze-back/internal/services/structurization"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/config"
+	infrakafka "github.com/Petr09Mitin/xrust-beze-back/internal/pkg/kafka"
+	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/logger"
+)
+
+func main() {
+	// init logger
+	log := logger.NewLogger()
+	log.Println("Starting structurizationd...")
+
+	// init cfg
+	cfg, err := config.NewStructurizationD()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load structurizationd config")
+	}
+
+	// init mongo
+	client, err := mongo.Connect(options.Client().ApplyURI(fmt.Sprintf(
+		"mongodb://%s:%s@%s:%d",
+		cfg.Mongo.Username,
+		cfg.Mongo.Password,
+		cfg.Mongo.Host,
+		cfg.Mongo.Port,
+	)))
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to connect to mongodb")
+		return
+	}
+	messagesCollection := client.Database(cfg.Mongo.Database).Collection("messages")
+	messagesRepo := message_repo.NewMessageRepo(messagesCollection, log)
+	channelsCollection := client.Database(cfg.Mongo.Database).Collection("channels")
+	channelRepo := channelrepo.NewChannelRepository(channelsCollection, log)
+	structurizationsCollection := client.Database(cfg.Mongo.Database).Collection("structurizations")
+	structurizationRepo := structurization_repo.NewStructurizationRepository(cfg.Services.StructurizationService, log)
+	structurizationCacheRepo := structurization_repo.NewStructurizationCacheRepo(structurizationsCollection, log)
+
+	// init kafka sub
+	kafkaSub, err := infrakafka.NewKafkaSubscriber(cfg.Kafka, cfg.Kafka.ConsumerGroup)
+	if err != nil {
+		log.Err(err).Msg("failed to connect to kafka sub")
+		return
+	}
+	// init kafka pub
+	kafkaPub, err := infrakafka.NewKafkaPublisher(cfg.Kafka)
+	if err != nil {
+		log.Err(err).Msg("failed to connect to kafka pub")
+		return
+	}
+	brokerRouter, err := infrakafka.NewBrokerRouter()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to initialize kafka msg_router")
+		return
+	}
+	structurizationService := structurization.NewStructurizationService(structurizationRepo, structurizationCacheRepo, messagesRepo, channelRepo, log, cfg)
+	d := structurizationd.NewStructurizationD(
+		structurizationService,
+		cfg.Kafka.StructurizationRequestTopic,
+		cfg.Kafka.SummaryRequestTopic,
+		message_repo.MessagePubTopic,
+		brokerRouter,
+		kafkaSub,
+		kafkaPub,
+		log,
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		err := d.GracefulStop()
+		if err != nil {
+			log.Error().Err(err).Msg("failed to gracefully stop structurizationd")
+		}
+	}()
+	if err = d.Run(ctx); err != nil {
+		log.Fatal().Err(err).Msg("error running structurizationd")
+		return
+	}
+}
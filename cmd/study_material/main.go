@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/Petr09Mitin/xrust-beze-back/internal/repository/rag_client"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -14,6 +15,7 @@ import (
 	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/config"
 	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/logger"
 	study_material_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/study_material"
+	study_material_grpc "github.com/Petr09Mitin/xrust-beze-back/internal/router/grpc/study_material"
 	study_material_http "github.com/Petr09Mitin/xrust-beze-back/internal/router/http/study_material"
 	"github.com/Petr09Mitin/xrust-beze-back/internal/router/middleware"
 	study_material_service "github.com/Petr09Mitin/xrust-beze-back/internal/services/study_material"
@@ -24,6 +26,7 @@ import (
 
 	authpb "github.com/Petr09Mitin/xrust-beze-back/proto/auth"
 	filepb "github.com/Petr09Mitin/xrust-beze-back/proto/file"
+	pb "github.com/Petr09Mitin/xrust-beze-back/proto/study_material"
 	userpb "github.com/Petr09Mitin/xrust-beze-back/proto/user"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -31,7 +34,7 @@ import (
 
 var (
 	httpServer *http.Server
-	// grpcServer *grpc.Server
+	grpcServer *grpc.Server
 )
 
 func main() {
@@ -44,7 +47,7 @@ func main() {
 	}
 
 	httpPort := cfg.HTTP.Port
-	// grpcPort := cfg.GRPC.Port
+	grpcPort := cfg.GRPC.Port
 
 	db, err := initMongo(log, cfg.Mongo)
 	if err != nil {
@@ -110,21 +113,22 @@ func main() {
 	}()
 
 	// gRPC сервер
-	// go func() {
-	// 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", grpcPort))
-	// 	if err != nil {
-	// 		errChan <- fmt.Errorf("failed to listen on port %d: %v", grpcPort, err)
-	// 		return
-	// 	}
-	// 	grpcServer = grpc.NewServer()
-	// 	studyMaterialGRPC := study_material_grpc.NewStudyMaterialServer(service, log)
-	// 	pb.RegisterStudyMaterialServiceServer(grpcServer, studyMaterialGRPC)
-
-	// 	log.Printf("gRPC server starting on port %d...", grpcPort)
-	// 	if err := grpcServer.Serve(lis); err != nil {
-	// 		errChan <- fmt.Errorf("failed to serve gRPC: %v", err)
-	// 	}
-	// }()
+	go func() {
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", grpcPort))
+		if err != nil {
+			errChan <- fmt.Errorf("failed to listen on port %d: %v", grpcPort, err)
+			return
+		}
+		grpcServer = grpc.NewServer()
+		// chat only reads cards, so writes are not exposed over gRPC
+		studyMaterialGRPC := study_material_grpc.NewReadOnlyStudyMaterialServer(study_material_grpc.NewStudyMaterialServer(service, log))
+		pb.RegisterStudyMaterialServiceServer(grpcServer, studyMaterialGRPC)
+
+		log.Printf("gRPC server starting on port %d...", grpcPort)
+		if err := grpcServer.Serve(lis); err != nil {
+			errChan <- fmt.Errorf("failed to serve gRPC: %v", err)
+		}
+	}()
 
 	// Ожидание сигнала завершения или ошибки
 	select {
@@ -141,7 +145,10 @@ func main() {
 	if err := httpServer.Shutdown(ctx); err != nil {
 		log.Fatal().Err(err).Msg("failed to shutdown http server")
 	}
-	// grpcServer.GracefulStop()
+	// server is not set if it failed to listen
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
 
 	log.Println("study_material microservice stopped")
 }
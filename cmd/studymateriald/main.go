@@ -45,7 +45,7 @@ func main() {
 	studyMaterialRepo := study_material_repo.NewStudyMaterialRepo(studyMaterialCollection, log)
 
 	// init kafka sub
-	kafkaSub, err := infrakafka.NewKafkaSubscriber(cfg.Kafka)
+	kafkaSub, err := infrakafka.NewKafkaSubscriber(cfg.Kafka, cfg.Kafka.ConsumerGroup)
 	if err != nil {
 		log.Err(err).Msg("failed to connect to kafka sub")
 		return
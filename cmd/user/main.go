@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/config"
+	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/grpcauth"
 	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/logger"
 	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/validation"
 	"github.com/Petr09Mitin/xrust-beze-back/internal/router/middleware"
@@ -85,6 +86,10 @@ func main() {
 
 	moderationRepo := moderation_repo.NewModerationRepository(cfg.Services.Moderation, log)
 	userRepo := user_repo.NewUserRepository(db, 10*time.Second, log)
+	err = userRepo.EnsureIndexes(context.Background())
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to ensure users indexes")
+	}
 	reviewColl := db.Collection("reviews")
 	reviewRepo := review_repo.NewReviewRepo(reviewColl, log)
 	userService := user_service.NewUserService(userRepo, moderationRepo, reviewRepo, fileGRPCClient, authClient, 10*time.Second, log)
@@ -121,7 +126,9 @@ func main() {
 			return
 		}
 
-		grpcServer = grpc.NewServer()
+		grpcServer = grpc.NewServer(
+			grpc.UnaryInterceptor(grpcauth.ServiceTokenServerInterceptor(cfg.GRPC.ServiceToken, grpc_handler.InternalMethods...)),
+		)
 		userGrpcService := grpc_handler.NewUserService(userService, log)
 		userpb.RegisterUserServiceServer(grpcServer, userGrpcService)
 
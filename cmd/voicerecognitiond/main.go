@@ -43,7 +43,7 @@ func main() {
 	aiVoiceRecognitionRepo := voice_recognition_repo.NewVoiceRecognitionRepo(cfg.Services.AIVoiceRecognition, log)
 
 	// init kafka sub
-	kafkaSub, err := infrakafka.NewKafkaSubscriber(cfg.Kafka)
+	kafkaSub, err := infrakafka.NewKafkaSubscriber(cfg.Kafka, cfg.Kafka.ConsumerGroup)
 	if err != nil {
 		log.Err(err).Msg("failed to connect to kafka sub")
 		return
@@ -0,0 +1,69 @@
+package chat_models
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Attachment is a file attached to the message with metadata collected by the file service.
+// Messages saved before metadata was introduced store only the filename, so both forms are read
+type Attachment struct {
+	Filename     string `json:"filename" bson:"filename"`
+	OriginalName string `json:"original_name,omitempty" bson:"original_name,omitempty"`
+	Size         int64  `json:"size,omitempty" bson:"size,omitempty"`
+	MimeType     string `json:"mime_type,omitempty" bson:"mime_type,omitempty"`
+	Width        int    `json:"width,omitempty" bson:"width,omitempty"`
+	Height       int    `json:"height,omitempty" bson:"height,omitempty"`
+}
+
+// attachmentFields has the fields of Attachment without its unmarshalers
+type attachmentFields Attachment
+
+// UnmarshalJSON accepts the bare filename as well, clients send temp files this way
+func (a *Attachment) UnmarshalJSON(data []byte) error {
+	var filename string
+	if err := json.Unmarshal(data, &filename); err == nil {
+		*a = Attachment{Filename: filename}
+		return nil
+	}
+	var fields attachmentFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	*a = Attachment(fields)
+	return nil
+}
+
+// UnmarshalBSONValue reads both the legacy filename string and the attachment document
+func (a *Attachment) UnmarshalBSONValue(typ byte, data []byte) error {
+	raw := bson.RawValue{Type: bson.Type(typ), Value: data}
+	switch raw.Type {
+	case bson.TypeString:
+		filename, ok := raw.StringValueOK()
+		if !ok {
+			return fmt.Errorf("invalid attachment filename")
+		}
+		*a = Attachment{Filename: filename}
+		return nil
+	case bson.TypeEmbeddedDocument:
+		var fields attachmentFields
+		if err := raw.Unmarshal(&fields); err != nil {
+			return err
+		}
+		*a = Attachment(fields)
+		return nil
+	default:
+		return fmt.Errorf("invalid attachment bson type: %s", raw.Type)
+	}
+}
+
+// AttachmentFilenames returns filenames of the attachments in the same order
+func AttachmentFilenames(attachments []Attachment) []string {
+	filenames := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		filenames = append(filenames, attachment.Filename)
+	}
+	return filenames
+}
@@ -3,17 +3,36 @@ package chat_models
 import "go.mongodb.org/mongo-driver/v2/bson"
 
 type BSONChannel struct {
-	ID      bson.ObjectID `bson:"_id,omitempty"`
-	UserIDs []string      `bson:"user_ids"`
-	Created int64         `bson:"created"`
-	Updated int64         `bson:"updated"`
+	ID               bson.ObjectID `bson:"_id,omitempty"`
+	Type             ChannelType   `bson:"type,omitempty"`
+	Title            string        `bson:"title,omitempty"`
+	OwnerID          string        `bson:"owner_id,omitempty"`
+	AdminIDs         []string      `bson:"admin_ids,omitempty"`
+	UserIDs          []string      `bson:"user_ids"`
+	PinnedMessageIDs []string      `bson:"pinned_message_ids,omitempty"`
+	MutedUserIDs     []string      `bson:"muted_user_ids,omitempty"`
+	Call             *CallState    `bson:"call,omitempty"`
+	Created          int64         `bson:"created"`
+	Updated          int64         `bson:"updated"`
 }
 
 func (c *BSONChannel) ToChannel() Channel {
+	channelType := c.Type
+	if channelType == "" {
+		// channels created before group chats were introduced are always direct
+		channelType = DirectChannelType
+	}
 	return Channel{
-		ID:      c.ID.Hex(),
-		UserIDs: c.UserIDs,
-		Created: c.Created,
-		Updated: c.Updated,
+		ID:               c.ID.Hex(),
+		Type:             channelType,
+		Title:            c.Title,
+		OwnerID:          c.OwnerID,
+		AdminIDs:         c.AdminIDs,
+		UserIDs:          c.UserIDs,
+		PinnedMessageIDs: c.PinnedMessageIDs,
+		MutedUserIDs:     c.MutedUserIDs,
+		Call:             c.Call,
+		Created:          c.Created,
+		Updated:          c.Updated,
 	}
 }
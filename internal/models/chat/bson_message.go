@@ -5,33 +5,55 @@ import (
 )
 
 type BSONMessage struct {
-	MessageID       bson.ObjectID `bson:"_id,omitempty"`
-	ChannelID       string        `bson:"channel_id"`
-	UserID          string        `bson:"user_id"`
-	PeerID          string        `bson:"peer_id"`
-	Payload         string        `bson:"payload"`
-	Structurized    string        `bson:"structurized,omitempty"`
-	Voice           string        `bson:"voice,omitempty"`
-	VoiceDuration   int64         `bson:"voice_duration,omitempty"`
-	RecognizedVoice string        `bson:"recognized_voice,omitempty"`
-	Attachments     []string      `bson:"attachments,omitempty"`
-	CreatedAt       int64         `bson:"created_at"`
-	UpdatedAt       int64         `bson:"updated_at"`
+	MessageID            bson.ObjectID         `bson:"_id,omitempty"`
+	ChannelID            string                `bson:"channel_id"`
+	UserID               string                `bson:"user_id"`
+	PeerID               string                `bson:"peer_id"`
+	ReplyToMessageID     string                `bson:"reply_to_message_id,omitempty"`
+	ForwardedFrom        *ForwardedFrom        `bson:"forwarded_from,omitempty"`
+	Payload              string                `bson:"payload"`
+	Structurized         string                `bson:"structurized,omitempty"`
+	StructurizedVersions []StructurizedVersion `bson:"structurized_versions,omitempty"`
+	Voice                string                `bson:"voice,omitempty"`
+	VoiceDuration        int64                 `bson:"voice_duration,omitempty"`
+	RecognizedVoice      string                `bson:"recognized_voice,omitempty"`
+	Attachments          []Attachment          `bson:"attachments,omitempty"`
+	StudyMaterialID      string                `bson:"study_material_id,omitempty"`
+	Summary              *SummaryInfo          `bson:"summary,omitempty"`
+	Reminder             *ReminderInfo         `bson:"reminder,omitempty"`
+	Call                 *CallInfo             `bson:"call,omitempty"`
+	Reactions            Reactions             `bson:"reactions,omitempty"`
+	Revisions            []MessageRevision     `bson:"revisions,omitempty"`
+	CreatedAt            int64                 `bson:"created_at"`
+	UpdatedAt            int64                 `bson:"updated_at"`
+	EditedAt             int64                 `bson:"edited_at,omitempty"`
+	DeletedAt            int64                 `bson:"deleted_at,omitempty"`
 }
 
 func (msg *BSONMessage) ToMessage() Message {
 	return Message{
-		MessageID:       msg.MessageID.Hex(),
-		ChannelID:       msg.ChannelID,
-		UserID:          msg.UserID,
-		PeerID:          msg.PeerID,
-		Payload:         msg.Payload,
-		Structurized:    msg.Structurized,
-		CreatedAt:       msg.CreatedAt,
-		UpdatedAt:       msg.UpdatedAt,
-		Voice:           msg.Voice,
-		RecognizedVoice: msg.RecognizedVoice,
-		Attachments:     msg.Attachments,
-		VoiceDuration:   msg.VoiceDuration,
+		MessageID:            msg.MessageID.Hex(),
+		ChannelID:            msg.ChannelID,
+		UserID:               msg.UserID,
+		PeerID:               msg.PeerID,
+		ReplyToMessageID:     msg.ReplyToMessageID,
+		ForwardedFrom:        msg.ForwardedFrom,
+		Payload:              msg.Payload,
+		Structurized:         msg.Structurized,
+		StructurizedVersions: msg.StructurizedVersions,
+		CreatedAt:            msg.CreatedAt,
+		UpdatedAt:            msg.UpdatedAt,
+		EditedAt:             msg.EditedAt,
+		Voice:                msg.Voice,
+		RecognizedVoice:      msg.RecognizedVoice,
+		Attachments:          msg.Attachments,
+		StudyMaterialID:      msg.StudyMaterialID,
+		Summary:              msg.Summary,
+		Reminder:             msg.Reminder,
+		Call:                 msg.Call,
+		VoiceDuration:        msg.VoiceDuration,
+		Reactions:            msg.Reactions,
+		Revisions:            msg.Revisions,
+		DeletedAt:            msg.DeletedAt,
 	}
 }
@@ -0,0 +1,69 @@
+package chat_models
+
+import (
+	"encoding/json"
+)
+
+const (
+	CallRingingStatus = "ringing"
+	CallActiveStatus  = "active"
+	CallEndedStatus   = "ended"
+	CallMissedStatus  = "missed"
+
+	AudioCallMedia = "audio"
+	VideoCallMedia = "video"
+)
+
+// CallState is the current or the last call of the channel, unix seconds.
+// CallID is the id of the call-start message
+type CallState struct {
+	CallID     string `json:"call_id" bson:"call_id"`
+	CallerID   string `json:"caller_id" bson:"caller_id"`
+	Media      string `json:"media" bson:"media"`
+	Status     string `json:"status" bson:"status"`
+	StartedAt  int64  `json:"started_at" bson:"started_at"`
+	AnsweredAt int64  `json:"answered_at,omitempty" bson:"answered_at,omitempty"`
+	EndedAt    int64  `json:"ended_at,omitempty" bson:"ended_at,omitempty"`
+}
+
+// IsFinished reports whether a new call can be started instead of this one
+func (c *CallState) IsFinished() bool {
+	return c.Status == CallEndedStatus || c.Status == CallMissedStatus
+}
+
+// Duration of the conversation in seconds, unanswered calls have zero duration
+func (c *CallState) Duration() int64 {
+	if c.AnsweredAt == 0 || c.EndedAt < c.AnsweredAt {
+		return 0
+	}
+	return c.EndedAt - c.AnsweredAt
+}
+
+// CallInfo marks the message as a call-start or call-end record in the channel history
+type CallInfo struct {
+	CallID   string `json:"call_id,omitempty" bson:"call_id,omitempty"`
+	CallerID string `json:"caller_id" bson:"caller_id"`
+	Media    string `json:"media" bson:"media"`
+	Status   string `json:"status" bson:"status"`
+	Duration int64  `json:"duration" bson:"duration"`
+}
+
+// NewCallInfo returns the record of the call in its current state
+func NewCallInfo(call CallState) *CallInfo {
+	return &CallInfo{
+		CallID:   call.CallID,
+		CallerID: call.CallerID,
+		Media:    call.Media,
+		Status:   call.Status,
+		Duration: call.Duration(),
+	}
+}
+
+// CallSignal is WebRTC signaling data, it is relayed between channel members as is and never stored
+type CallSignal struct {
+	CallID    string          `json:"call_id,omitempty"`
+	Media     string          `json:"media,omitempty"`
+	SDP       string          `json:"sdp,omitempty"`
+	Candidate json.RawMessage `json:"candidate,omitempty"`
+	Status    string          `json:"status,omitempty"`
+}
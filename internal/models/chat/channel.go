@@ -4,15 +4,45 @@ import (
 	"encoding/json"
 	"fmt"
 	user_model "github.com/Petr09Mitin/xrust-beze-back/internal/models/user"
+	"slices"
+)
+
+type ChannelType string
+
+const (
+	MaxPinnedMessages = 50
+)
+
+const (
+	DirectChannelType = ChannelType("direct")
+	GroupChannelType  = ChannelType("group")
 )
 
 type Channel struct {
-	ID          string            `json:"channel_id" bson:"_id,omitempty"`
-	UserIDs     []string          `json:"user_ids" bson:"user_ids"`
-	Users       []user_model.User `json:"users,omitempty" bson:"-"`
-	LastMessage *Message          `json:"last_message" bson:"-"`
-	Created     int64             `json:"created" bson:"created"`
-	Updated     int64             `json:"updated" bson:"updated"`
+	ID                string            `json:"channel_id" bson:"_id,omitempty"`
+	Type              ChannelType       `json:"type,omitempty" bson:"type,omitempty"`
+	Title             string            `json:"title,omitempty" bson:"title,omitempty"`
+	OwnerID           string            `json:"owner_id,omitempty" bson:"owner_id,omitempty"`
+	AdminIDs          []string          `json:"admin_ids,omitempty" bson:"admin_ids,omitempty"`
+	UserIDs           []string          `json:"user_ids" bson:"user_ids"`
+	PinnedMessageIDs  []string          `json:"pinned_message_ids,omitempty" bson:"pinned_message_ids,omitempty"`
+	PinnedMessages    []Message         `json:"pinned_messages,omitempty" bson:"-"`
+	Call              *CallState        `json:"call,omitempty" bson:"call,omitempty"`
+	MutedUserIDs      []string          `json:"-" bson:"muted_user_ids,omitempty"`
+	Muted             bool              `json:"muted" bson:"-"`
+	Users             []user_model.User `json:"users,omitempty" bson:"-"`
+	LastMessage       *Message          `json:"last_message" bson:"-"`
+	UnreadCount       int64             `json:"unread_count" bson:"-"`
+	LastReadMessageID string            `json:"last_read_message_id,omitempty" bson:"-"`
+	OnlineUserIDs     []string          `json:"online_user_ids" bson:"-"`
+	Created           int64             `json:"created" bson:"created"`
+	Updated           int64             `json:"updated" bson:"updated"`
+}
+
+// GroupChannelRequest is used to create and update group channels
+type GroupChannelRequest struct {
+	Title   string   `json:"title"`
+	UserIDs []string `json:"user_ids"`
 }
 
 func (c *Channel) Encode() []byte {
@@ -23,3 +53,17 @@ func (c *Channel) Encode() []byte {
 	}
 	return result
 }
+
+func (c *Channel) IsGroup() bool {
+	return c.Type == GroupChannelType
+}
+
+// IsMutedBy reports whether user has muted notifications from the channel
+func (c *Channel) IsMutedBy(userID string) bool {
+	return slices.Contains(c.MutedUserIDs, userID)
+}
+
+// IsAdmin reports whether user can manage group members. Owner is always an admin
+func (c *Channel) IsAdmin(userID string) bool {
+	return c.OwnerID == userID || slices.Contains(c.AdminIDs, userID)
+}
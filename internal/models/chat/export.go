@@ -0,0 +1,87 @@
+package chat_models
+
+import (
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+)
+
+type ExportFormat string
+
+const (
+	MarkdownExportFormat = ExportFormat("md")
+	JSONExportFormat     = ExportFormat("json")
+	HTMLExportFormat     = ExportFormat("html")
+)
+
+// ParseExportFormat returns markdown if the format is not set
+func ParseExportFormat(format string) (ExportFormat, error) {
+	switch ExportFormat(format) {
+	case "":
+		return MarkdownExportFormat, nil
+	case MarkdownExportFormat, JSONExportFormat, HTMLExportFormat:
+		return ExportFormat(format), nil
+	default:
+		return "", custom_errors.ErrInvalidExportFormat
+	}
+}
+
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case JSONExportFormat:
+		return "application/json; charset=utf-8"
+	case HTMLExportFormat:
+		return "text/html; charset=utf-8"
+	default:
+		return "text/markdown; charset=utf-8"
+	}
+}
+
+// ExportedChannel opens the export, members are the current ones
+type ExportedChannel struct {
+	ChannelID  string         `json:"channel_id"`
+	Type       ChannelType    `json:"type"`
+	Title      string         `json:"title,omitempty"`
+	Members    []ExportedUser `json:"members"`
+	ExportedAt int64          `json:"exported_at"`
+}
+
+type ExportedUser struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// ExportedMessage is readable without the service: authors are resolved to usernames and files to links
+type ExportedMessage struct {
+	MessageID         string               `json:"message_id"`
+	Event             MsgEvent             `json:"event"`
+	UserID            string               `json:"user_id"`
+	Username          string               `json:"username"`
+	ReplyToMessageID  string               `json:"reply_to_message_id,omitempty"`
+	ForwardedFrom     *ForwardedFrom       `json:"forwarded_from,omitempty"`
+	Payload           string               `json:"payload,omitempty"`
+	VoiceURL          string               `json:"voice_url,omitempty"`
+	RecognizedVoice   string               `json:"recognized_voice,omitempty"`
+	Structurized      string               `json:"structurized,omitempty"`
+	Attachments       []ExportedAttachment `json:"attachments,omitempty"`
+	StudyMaterialID   string               `json:"study_material_id,omitempty"`
+	StudyMaterialName string               `json:"study_material_name,omitempty"`
+	Call              *CallInfo            `json:"call,omitempty"`
+	// Edited is set only by content edits, reactions, pins and explanations also change UpdatedAt
+	Edited    bool  `json:"edited,omitempty"`
+	CreatedAt int64 `json:"created_at"`
+	UpdatedAt int64 `json:"updated_at"`
+}
+
+type ExportedAttachment struct {
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	MimeType string `json:"mime_type,omitempty"`
+	Size     int64  `json:"size,omitempty"`
+}
+
+// StudyMaterialTitle is the name of the shared study material, or its id if the material is not found
+func (m ExportedMessage) StudyMaterialTitle() string {
+	if m.StudyMaterialName != "" {
+		return m.StudyMaterialName
+	}
+	return m.StudyMaterialID
+}
@@ -0,0 +1,34 @@
+package chat_models
+
+// ForwardedFrom references the original message of the forwarded copy
+type ForwardedFrom struct {
+	MessageID string `json:"message_id" bson:"message_id"`
+	ChannelID string `json:"channel_id" bson:"channel_id"`
+	UserID    string `json:"user_id" bson:"user_id"`
+	CreatedAt int64  `json:"created_at" bson:"created_at"`
+}
+
+// NewForwardedFrom references the message, forwarding a forwarded copy keeps the reference to the original
+func NewForwardedFrom(msg Message) *ForwardedFrom {
+	if msg.ForwardedFrom != nil {
+		forwardedFrom := *msg.ForwardedFrom
+		return &forwardedFrom
+	}
+	return &ForwardedFrom{
+		MessageID: msg.MessageID,
+		ChannelID: msg.ChannelID,
+		UserID:    msg.UserID,
+		CreatedAt: msg.CreatedAt,
+	}
+}
+
+// IsForwardable reports whether the message content can be copied to another channel.
+// Voice, calls and generated messages belong to their channel
+func (msg *Message) IsForwardable() bool {
+	switch msg.KindEvent() {
+	case TextMsgEvent, StudyMaterialEvent:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,57 @@
+package chat_models
+
+import (
+	"errors"
+
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+)
+
+const (
+	AckFrameType   = MsgType("ack")
+	ErrorFrameType = MsgType("error")
+)
+
+// AckFrame confirms that the client frame with RequestID was processed
+type AckFrame struct {
+	Type      MsgType  `json:"type"`
+	RequestID string   `json:"request_id"`
+	Event     MsgEvent `json:"event,omitempty"`
+	MessageID string   `json:"message_id,omitempty"`
+}
+
+// ErrorFrame is sent when the client frame failed.
+// Code is stable and machine-readable, Error is for humans only
+type ErrorFrame struct {
+	Type         MsgType  `json:"type"`
+	RequestID    string   `json:"request_id,omitempty"`
+	Event        MsgEvent `json:"event,omitempty"`
+	Code         string   `json:"code"`
+	Error        string   `json:"error"`
+	Retryable    bool     `json:"retryable"`
+	RetryAfterMs int64    `json:"retry_after_ms,omitempty"`
+}
+
+func NewAckFrame(requestID string, event MsgEvent, messageID string) AckFrame {
+	return AckFrame{
+		Type:      AckFrameType,
+		RequestID: requestID,
+		Event:     event,
+		MessageID: messageID,
+	}
+}
+
+func NewErrorFrame(requestID string, event MsgEvent, err error) ErrorFrame {
+	frame := ErrorFrame{
+		Type:      ErrorFrameType,
+		RequestID: requestID,
+		Event:     event,
+		Code:      custom_errors.MapErrorToWSCode(err),
+		Error:     err.Error(),
+		Retryable: custom_errors.IsRetryableError(err),
+	}
+	var rlErr *custom_errors.RateLimitedError
+	if errors.As(err, &rlErr) {
+		frame.RetryAfterMs = rlErr.RetryAfter.Milliseconds()
+	}
+	return frame
+}
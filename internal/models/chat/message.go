@@ -13,28 +13,73 @@ const (
 	StructurizationEvent = MsgEvent("EventStructurization")
 	VoiceMessageEvent    = MsgEvent("EventVoice")
 	VoiceRecognizedEvent = MsgEvent("EventVoiceRecognized")
+	MemberJoinedEvent    = MsgEvent("EventMemberJoined")
+	MemberLeftEvent      = MsgEvent("EventMemberLeft")
+	ReadEvent            = MsgEvent("EventRead")
+	TypingEvent          = MsgEvent("EventTyping")
+	PresenceEvent        = MsgEvent("EventPresence")
+	ReactionEvent        = MsgEvent("EventReaction")
+	ResyncEvent          = MsgEvent("EventResync")
+	SummaryEvent         = MsgEvent("EventSummary")
+	PinEvent             = MsgEvent("EventPin")
+	ReminderEvent        = MsgEvent("EventReminder")
+	CallEvent            = MsgEvent("EventCall")
+	ForwardEvent         = MsgEvent("EventForward")
+	StudyMaterialEvent   = MsgEvent("EventStudyMaterial")
+
+	CallOfferEvent        = MsgEvent("EventCallOffer")
+	CallAnswerEvent       = MsgEvent("EventCallAnswer")
+	CallIceCandidateEvent = MsgEvent("EventCallIceCandidate")
+	CallEndEvent          = MsgEvent("EventCallEnd")
 
 	SendMessageType   = MsgType("send_message")
 	UpdateMessageType = MsgType("update_message")
 	DeleteMessageType = MsgType("delete_message")
+
+	TypingStartedStatus   = "started"
+	TypingStoppedStatus   = "stopped"
+	PresenceOnlineStatus  = "online"
+	PresenceOfflineStatus = "offline"
 )
 
 type Message struct {
-	MessageID       string         `json:"message_id,omitempty" bson:"_id,omitempty"`
-	Event           MsgEvent       `json:"event,omitempty" bson:"-"`
-	Type            MsgType        `json:"type,omitempty" bson:"-"`
-	ChannelID       string         `json:"channel_id,omitempty" bson:"channel_id"`
-	UserID          string         `json:"user_id,omitempty" bson:"user_id"`
-	PeerID          string         `json:"peer_id,omitempty" bson:"peer_id"`
-	ReceiverIDs     map[string]any `json:"receiver_ids,omitempty" bson:"-"`
-	Payload         string         `json:"payload,omitempty" bson:"payload"`
-	Structurized    string         `json:"structurized,omitempty" bson:"structurized"`
-	Voice           string         `json:"voice,omitempty" bson:"voice"`
-	VoiceDuration   int64          `json:"voice_duration,omitempty" bson:"voice_duration"`
-	RecognizedVoice string         `json:"recognized_voice,omitempty" bson:"recognized_voice"`
-	Attachments     []string       `json:"attachments,omitempty" bson:"attachments"`
-	CreatedAt       int64          `json:"created_at,omitempty" bson:"created_at"`
-	UpdatedAt       int64          `json:"updated_at,omitempty" bson:"updated_at"`
+	MessageID            string                `json:"message_id,omitempty" bson:"_id,omitempty"`
+	RequestID            string                `json:"request_id,omitempty" bson:"-"`
+	Event                MsgEvent              `json:"event,omitempty" bson:"-"`
+	Type                 MsgType               `json:"type,omitempty" bson:"-"`
+	ChannelID            string                `json:"channel_id,omitempty" bson:"channel_id"`
+	UserID               string                `json:"user_id,omitempty" bson:"user_id"`
+	PeerID               string                `json:"peer_id,omitempty" bson:"peer_id"`
+	ReplyToMessageID     string                `json:"reply_to_message_id,omitempty" bson:"reply_to_message_id,omitempty"`
+	ReplyTo              *QuotedMessage        `json:"reply_to,omitempty" bson:"-"`
+	ForwardedFrom        *ForwardedFrom        `json:"forwarded_from,omitempty" bson:"forwarded_from,omitempty"`
+	ReceiverIDs          map[string]any        `json:"receiver_ids,omitempty" bson:"-"`
+	MutedReceiverIDs     []string              `json:"muted_receiver_ids,omitempty" bson:"-"`
+	Silent               bool                  `json:"silent,omitempty" bson:"-"`
+	MemberIDs            []string              `json:"member_ids,omitempty" bson:"-"`
+	PinnedMessageIDs     []string              `json:"pinned_message_ids,omitempty" bson:"-"`
+	Status               string                `json:"status,omitempty" bson:"-"`
+	Payload              string                `json:"payload,omitempty" bson:"payload"`
+	Structurized         string                `json:"structurized,omitempty" bson:"structurized"`
+	StructurizedVersions []StructurizedVersion `json:"structurized_versions,omitempty" bson:"structurized_versions,omitempty"`
+	Regenerate           bool                  `json:"regenerate,omitempty" bson:"-"`
+	Voice                string                `json:"voice,omitempty" bson:"voice"`
+	VoiceDuration        int64                 `json:"voice_duration,omitempty" bson:"voice_duration"`
+	RecognizedVoice      string                `json:"recognized_voice,omitempty" bson:"recognized_voice"`
+	Attachments          []Attachment          `json:"attachments,omitempty" bson:"attachments"`
+	StudyMaterialID      string                `json:"study_material_id,omitempty" bson:"study_material_id,omitempty"`
+	StudyMaterial        *StudyMaterialCard    `json:"study_material,omitempty" bson:"-"`
+	Summary              *SummaryInfo          `json:"summary,omitempty" bson:"summary,omitempty"`
+	Reminder             *ReminderInfo         `json:"reminder,omitempty" bson:"reminder,omitempty"`
+	Call                 *CallInfo             `json:"call,omitempty" bson:"call,omitempty"`
+	Signal               *CallSignal           `json:"signal,omitempty" bson:"-"`
+	Reaction             string                `json:"reaction,omitempty" bson:"-"`
+	Reactions            Reactions             `json:"reactions,omitempty" bson:"reactions,omitempty"`
+	Revisions            []MessageRevision     `json:"-" bson:"revisions,omitempty"`
+	CreatedAt            int64                 `json:"created_at,omitempty" bson:"created_at"`
+	UpdatedAt            int64                 `json:"updated_at,omitempty" bson:"updated_at"`
+	EditedAt             int64                 `json:"edited_at,omitempty" bson:"edited_at,omitempty"`
+	DeletedAt            int64                 `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
 }
 
 func (msg *Message) Encode() []byte {
@@ -56,6 +101,26 @@ func DecodeToMessage(msg []byte) (*Message, error) {
 	return &message, nil
 }
 
+// KindEvent returns the event the message was created with, it is not stored in db
+func (msg *Message) KindEvent() MsgEvent {
+	if msg.Summary != nil {
+		return SummaryEvent
+	}
+	if msg.Reminder != nil {
+		return ReminderEvent
+	}
+	if msg.Call != nil {
+		return CallEvent
+	}
+	if msg.Voice != "" {
+		return VoiceMessageEvent
+	}
+	if msg.StudyMaterialID != "" {
+		return StudyMaterialEvent
+	}
+	return TextMsgEvent
+}
+
 func (msg *Message) SetReceiverIDs(receiverIDs []string) {
 	msg.ReceiverIDs = make(map[string]any, len(receiverIDs))
 	for _, receiverID := range receiverIDs {
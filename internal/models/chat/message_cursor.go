@@ -0,0 +1,61 @@
+package chat_models
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+)
+
+// MessageCursor points to a message in channel history. created_at is not unique,
+// so message id is used as a tie-breaker
+type MessageCursor struct {
+	CreatedAt int64
+	MessageID string
+}
+
+// MessagesQuery describes a page of channel history.
+// Before and After are mutually exclusive, with no cursor the newest messages are returned
+type MessagesQuery struct {
+	Before *MessageCursor
+	After  *MessageCursor
+	Limit  int64
+}
+
+type MessagesPage struct {
+	Messages   []Message `json:"messages"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+func NewMessageCursor(msg Message) *MessageCursor {
+	return &MessageCursor{
+		CreatedAt: msg.CreatedAt,
+		MessageID: msg.MessageID,
+	}
+}
+
+// Encode returns an opaque url-safe representation of the cursor
+func (c *MessageCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s", c.CreatedAt, c.MessageID)))
+}
+
+func DecodeMessageCursor(cursor string) (*MessageCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, custom_errors.ErrInvalidCursor
+	}
+	createdAtStr, messageID, found := strings.Cut(string(decoded), ":")
+	if !found || messageID == "" {
+		return nil, custom_errors.ErrInvalidCursor
+	}
+	createdAt, err := strconv.ParseInt(createdAtStr, 10, 64)
+	if err != nil {
+		return nil, custom_errors.ErrInvalidCursor
+	}
+	return &MessageCursor{
+		CreatedAt: createdAt,
+		MessageID: messageID,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package chat_models
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+)
+
+func TestMessageCursorEncodeDecode(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor MessageCursor
+	}{
+		{
+			name:   "object id",
+			cursor: MessageCursor{CreatedAt: 1700000000, MessageID: "6650f1a2b3c4d5e6f7a8b9c0"},
+		},
+		{
+			name:   "zero time",
+			cursor: MessageCursor{CreatedAt: 0, MessageID: "id"},
+		},
+		{
+			name:   "id with separator",
+			cursor: MessageCursor{CreatedAt: 42, MessageID: "a:b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := DecodeMessageCursor(tt.cursor.Encode())
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if *decoded != tt.cursor {
+				t.Fatalf("got %+v, want %+v", *decoded, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeMessageCursorRejectsInvalid(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not base64", cursor: "!!!"},
+		{name: "padded base64", cursor: base64.URLEncoding.EncodeToString([]byte("1:id"))},
+		{name: "no separator", cursor: encode("1700000000")},
+		{name: "no message id", cursor: encode("1700000000:")},
+		{name: "time is not a number", cursor: encode("yesterday:id")},
+		{name: "empty", cursor: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeMessageCursor(tt.cursor)
+			if !errors.Is(err, custom_errors.ErrInvalidCursor) {
+				t.Fatalf("got %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
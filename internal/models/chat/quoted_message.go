@@ -0,0 +1,34 @@
+package chat_models
+
+const maxQuotePreviewLength = 200
+
+// QuotedMessage is a short preview of the message being replied to
+type QuotedMessage struct {
+	MessageID      string `json:"message_id"`
+	UserID         string `json:"user_id"`
+	Preview        string `json:"preview,omitempty"`
+	HasVoice       bool   `json:"has_voice,omitempty"`
+	HasAttachments bool   `json:"has_attachments,omitempty"`
+	Deleted        bool   `json:"deleted,omitempty"`
+	CreatedAt      int64  `json:"created_at"`
+}
+
+func NewQuotedMessage(msg Message) *QuotedMessage {
+	preview := msg.Payload
+	if preview == "" {
+		preview = msg.RecognizedVoice
+	}
+	runes := []rune(preview)
+	if len(runes) > maxQuotePreviewLength {
+		preview = string(runes[:maxQuotePreviewLength]) + "…"
+	}
+	return &QuotedMessage{
+		MessageID:      msg.MessageID,
+		UserID:         msg.UserID,
+		Preview:        preview,
+		HasVoice:       msg.Voice != "",
+		HasAttachments: len(msg.Attachments) > 0,
+		Deleted:        msg.IsDeleted(),
+		CreatedAt:      msg.CreatedAt,
+	}
+}
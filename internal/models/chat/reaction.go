@@ -0,0 +1,22 @@
+package chat_models
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const maxReactionLength = 32
+
+// Reactions maps emoji to ids of users who reacted with it
+type Reactions map[string][]string
+
+// IsValidReaction checks that emoji can be safely used as a key of the reactions document
+func IsValidReaction(reaction string) bool {
+	if reaction == "" || !utf8.ValidString(reaction) || len(reaction) > maxReactionLength {
+		return false
+	}
+	if strings.HasPrefix(reaction, "$") || strings.ContainsAny(reaction, ". \t\n") {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,17 @@
+package chat_models
+
+// ReadState is the last message read by user in a channel
+type ReadState struct {
+	ChannelID         string `json:"channel_id" bson:"channel_id"`
+	UserID            string `json:"user_id" bson:"user_id"`
+	LastReadMessageID string `json:"last_read_message_id" bson:"last_read_message_id"`
+	LastReadAt        int64  `json:"last_read_at" bson:"last_read_at"` // created_at of the last read message
+	UpdatedAt         int64  `json:"updated_at" bson:"updated_at"`
+}
+
+func (r *ReadState) Cursor() *MessageCursor {
+	return &MessageCursor{
+		CreatedAt: r.LastReadAt,
+		MessageID: r.LastReadMessageID,
+	}
+}
@@ -0,0 +1,72 @@
+package chat_models
+
+// MaxMessageRevisions is how many previous versions of a message are kept, the oldest ones are dropped
+const MaxMessageRevisions = 20
+
+// MessageRevision is a previous version of an edited message
+type MessageRevision struct {
+	Payload     string       `json:"payload" bson:"payload"`
+	Attachments []Attachment `json:"attachments" bson:"attachments"`
+	// CreatedAt is when this version appeared, ReplacedAt - when it was edited
+	CreatedAt  int64 `json:"created_at" bson:"created_at"`
+	ReplacedAt int64 `json:"replaced_at" bson:"replaced_at"`
+}
+
+// NewMessageRevision keeps the current content of msg. updated_at is also bumped by reactions and structurization,
+// so the version is dated by the last edit
+func NewMessageRevision(msg Message, replacedAt int64) MessageRevision {
+	createdAt := msg.EditedAt
+	if createdAt == 0 {
+		createdAt = msg.CreatedAt
+	}
+	return MessageRevision{
+		Payload:     msg.Payload,
+		Attachments: msg.Attachments,
+		CreatedAt:   createdAt,
+		ReplacedAt:  replacedAt,
+	}
+}
+
+// DroppedRevisionAttachments returns filenames which are referenced only by the revisions
+// dropped when revision is added to msg, they are not deleted with the message anymore
+func DroppedRevisionAttachments(msg Message, revision MessageRevision, attachments []Attachment) []string {
+	revisions := append(append(make([]MessageRevision, 0, len(msg.Revisions)+1), msg.Revisions...), revision)
+	if len(revisions) <= MaxMessageRevisions {
+		return nil
+	}
+	dropped := revisions[:len(revisions)-MaxMessageRevisions]
+	kept := Message{
+		Attachments: attachments,
+		Revisions:   revisions[len(revisions)-MaxMessageRevisions:],
+	}
+	keptFilenames := make(map[string]struct{})
+	for _, filename := range kept.AllAttachments() {
+		keptFilenames[filename] = struct{}{}
+	}
+	res := make([]string, 0)
+	for _, filename := range (&Message{Revisions: dropped}).AllAttachments() {
+		if _, ok := keptFilenames[filename]; !ok {
+			res = append(res, filename)
+		}
+	}
+	return res
+}
+
+// AllAttachments returns filenames of attachments of the message and all its revisions
+func (msg *Message) AllAttachments() []string {
+	seen := make(map[string]struct{}, len(msg.Attachments))
+	res := make([]string, 0, len(msg.Attachments))
+	add := func(attachments []Attachment) {
+		for _, attachment := range attachments {
+			if _, ok := seen[attachment.Filename]; !ok {
+				seen[attachment.Filename] = struct{}{}
+				res = append(res, attachment.Filename)
+			}
+		}
+	}
+	add(msg.Attachments)
+	for _, revision := range msg.Revisions {
+		add(revision.Attachments)
+	}
+	return res
+}
@@ -0,0 +1,70 @@
+package chat_models
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type ScheduledMessageKind string
+type ScheduledMessageStatus string
+
+const (
+	ScheduledTextKind       = ScheduledMessageKind("message")
+	ScheduledReminderKind   = ScheduledMessageKind("session_reminder")
+	PendingScheduledStatus  = ScheduledMessageStatus("pending")
+	SentScheduledStatus     = ScheduledMessageStatus("sent")
+	CanceledScheduledStatus = ScheduledMessageStatus("canceled")
+	FailedScheduledStatus   = ScheduledMessageStatus("failed")
+)
+
+// ScheduledMessage is posted to the channel by schedulerd at SendAt (unix seconds)
+type ScheduledMessage struct {
+	ID        bson.ObjectID          `json:"id" bson:"_id,omitempty"`
+	Kind      ScheduledMessageKind   `json:"kind" bson:"kind"`
+	ChannelID string                 `json:"channel_id" bson:"channel_id"`
+	UserID    string                 `json:"user_id" bson:"user_id"`
+	Payload   string                 `json:"payload,omitempty" bson:"payload,omitempty"`
+	SessionAt int64                  `json:"session_at,omitempty" bson:"session_at,omitempty"`
+	SendAt    int64                  `json:"send_at" bson:"send_at"`
+	Status    ScheduledMessageStatus `json:"status" bson:"status"`
+	// MessageID is the id of the posted message
+	MessageID     string `json:"message_id,omitempty" bson:"message_id,omitempty"`
+	Attempts      int    `json:"-" bson:"attempts"`
+	LastError     string `json:"-" bson:"last_error,omitempty"`
+	NextAttemptAt int64  `json:"-" bson:"next_attempt_at"`
+	CreatedAt     int64  `json:"created_at" bson:"created_at"`
+	UpdatedAt     int64  `json:"updated_at" bson:"updated_at"`
+}
+
+type ScheduledMessageRequest struct {
+	Kind      ScheduledMessageKind `json:"kind"`
+	ChannelID string               `json:"channel_id"`
+	Payload   string               `json:"payload"`
+	SessionAt int64                `json:"session_at"`
+	SendAt    int64                `json:"send_at"`
+}
+
+// ReminderInfo marks the message as a reminder about the session at SessionAt
+type ReminderInfo struct {
+	SessionAt int64 `json:"session_at" bson:"session_at"`
+}
+
+// ToMessage builds the channel message to post
+func (sm *ScheduledMessage) ToMessage(createdAt int64) Message {
+	msg := Message{
+		Event:       TextMsgEvent,
+		Type:        SendMessageType,
+		ChannelID:   sm.ChannelID,
+		UserID:      sm.UserID,
+		Payload:     sm.Payload,
+		Attachments: make([]Attachment, 0),
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+	}
+	if sm.Kind == ScheduledReminderKind {
+		msg.Event = ReminderEvent
+		msg.Reminder = &ReminderInfo{
+			SessionAt: sm.SessionAt,
+		}
+	}
+	return msg
+}
@@ -0,0 +1,28 @@
+package chat_models
+
+type SearchField string
+
+const (
+	PayloadSearchField         = SearchField("payload")
+	RecognizedVoiceSearchField = SearchField("recognized_voice")
+	StructurizedSearchField    = SearchField("structurized")
+)
+
+// SearchHighlight is a matched part of the snippet, offsets are in runes, end is exclusive
+type SearchHighlight struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+type SearchResult struct {
+	Message    Message           `json:"message"`
+	Channel    *Channel          `json:"channel,omitempty"`
+	Field      SearchField       `json:"field,omitempty"`
+	Snippet    string            `json:"snippet"`
+	Highlights []SearchHighlight `json:"highlights"`
+}
+
+type SearchPage struct {
+	Results    []SearchResult `json:"results"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,88 @@
+package chat_models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+const (
+	// statuses of EventStructurization updates
+	StructurizationPendingStatus = "structurization_pending"
+	StructurizationDoneStatus    = "structurization_done"
+	StructurizationFailedStatus  = "structurization_failed"
+)
+
+// StructurizedVersion is one of generated explanations, the last one is the current Message.Structurized
+type StructurizedVersion struct {
+	Structurized string `json:"structurized" bson:"structurized"`
+	// Hash is StructurizationHash of the question and answer the explanation was made for
+	Hash      string `json:"-" bson:"hash,omitempty"`
+	CreatedAt int64  `json:"created_at" bson:"created_at"`
+}
+
+// StructurizationTask is sent to structurizationd, Message keeps receivers of the final update
+type StructurizationTask struct {
+	Message  Message `json:"message"`
+	Question string  `json:"question"`
+	Answer   string  `json:"answer"`
+	Hash     string  `json:"hash"`
+}
+
+// StructurizationHash identifies the explanator input, equal inputs give reusable explanations
+func StructurizationHash(question, answer string) string {
+	h := sha256.New()
+	h.Write([]byte(question))
+	h.Write([]byte{0})
+	h.Write([]byte(answer))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// StructurizationInFlightKey identifies the explanation being generated for the message
+func StructurizationInFlightKey(messageID, hash string) string {
+	return "in_flight:" + messageID + ":" + hash
+}
+
+// HasStructurizationFor tells if the current explanation was made for the input with the hash.
+// Explanations stored without hash are not trusted, the question or answer could be edited since
+func (msg *Message) HasStructurizationFor(hash string) bool {
+	if msg.Structurized == "" || len(msg.StructurizedVersions) == 0 {
+		return false
+	}
+	return msg.StructurizedVersions[len(msg.StructurizedVersions)-1].Hash == hash
+}
+
+// NewStructurizedVersions returns versions to append when the message gets new explanation.
+// Explanations made before versions were stored are kept as the first version
+func (msg *Message) NewStructurizedVersions(structurized, hash string, createdAt int64) []StructurizedVersion {
+	versions := make([]StructurizedVersion, 0, 2)
+	if msg.Structurized != "" && len(msg.StructurizedVersions) == 0 {
+		versions = append(versions, StructurizedVersion{
+			Structurized: msg.Structurized,
+			CreatedAt:    msg.UpdatedAt,
+		})
+	}
+	return append(versions, StructurizedVersion{
+		Structurized: structurized,
+		Hash:         hash,
+		CreatedAt:    createdAt,
+	})
+}
+
+func (t *StructurizationTask) InFlightKey() string {
+	return StructurizationInFlightKey(t.Message.MessageID, t.Hash)
+}
+
+func (t *StructurizationTask) Encode() ([]byte, error) {
+	return json.Marshal(t)
+}
+
+func DecodeToStructurizationTask(data []byte) (*StructurizationTask, error) {
+	var task StructurizationTask
+	err := json.Unmarshal(data, &task)
+	if err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
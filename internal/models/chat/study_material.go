@@ -0,0 +1,16 @@
+package chat_models
+
+// StudyMaterialCard is a study material shared in the chat, only StudyMaterialID is stored with the message
+type StudyMaterialCard struct {
+	ID       string              `json:"id"`
+	Name     string              `json:"name"`
+	Filename string              `json:"filename"`
+	Tags     []string            `json:"tags"`
+	Author   StudyMaterialAuthor `json:"author"`
+}
+
+type StudyMaterialAuthor struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Avatar   string `json:"avatar,omitempty"`
+}
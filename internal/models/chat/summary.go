@@ -0,0 +1,49 @@
+package chat_models
+
+import (
+	"encoding/json"
+)
+
+const (
+	SummaryPendingStatus = "summary_pending"
+	SummaryFailedStatus  = "summary_failed"
+	// SummaryPinFailedStatus is sent to the requester when the summary is saved but could not be pinned
+	SummaryPinFailedStatus = "summary_pin_failed"
+)
+
+// SummaryRequest is a window of the conversation to summarize, unix seconds
+type SummaryRequest struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+}
+
+// SummaryInfo marks the message as AI summary of the channel messages created in [From, To]
+type SummaryInfo struct {
+	From          int64 `json:"from" bson:"from"`
+	To            int64 `json:"to" bson:"to"`
+	MessagesCount int   `json:"messages_count" bson:"messages_count"`
+}
+
+// SummaryTask is sent to structurizationd, Usernames are used to make the transcript readable
+type SummaryTask struct {
+	ChannelID   string            `json:"channel_id"`
+	UserID      string            `json:"user_id"`
+	From        int64             `json:"from"`
+	To          int64             `json:"to"`
+	Usernames   map[string]string `json:"usernames"`
+	ReceiverIDs []string          `json:"receiver_ids"`
+}
+
+func (t *SummaryTask) Encode() ([]byte, error) {
+	return json.Marshal(t)
+}
+
+func DecodeToSummaryTask(data []byte) (*SummaryTask, error) {
+	var task SummaryTask
+	err := json.Unmarshal(data, &task)
+	if err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
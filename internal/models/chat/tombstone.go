@@ -0,0 +1,19 @@
+package chat_models
+
+func (msg *Message) IsDeleted() bool {
+	return msg.DeletedAt != 0
+}
+
+// NewTombstone returns what is left from the deleted message: everything except content
+func NewTombstone(msg Message, deletedAt int64) Message {
+	return Message{
+		MessageID:        msg.MessageID,
+		ChannelID:        msg.ChannelID,
+		UserID:           msg.UserID,
+		PeerID:           msg.PeerID,
+		ReplyToMessageID: msg.ReplyToMessageID,
+		CreatedAt:        msg.CreatedAt,
+		UpdatedAt:        deletedAt,
+		DeletedAt:        deletedAt,
+	}
+}
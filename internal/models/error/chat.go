@@ -15,5 +15,40 @@ var (
 	ErrStructurizationUnavailable       = errors.New("structurization is temporary unavailable, try again later")
 	ErrNoUserIDOrPeerID                 = fmt.Errorf("%w: no user id or peer id", ErrBadRequest)
 	ErrParsingStudyMaterialsUnavailable = errors.New("parsing study materials is temporary unavailable, try again later")
+	ErrBlockedUsersUnavailable          = errors.New("blocked users are temporary unavailable, try again later")
 	ErrCannotStructurizeEmptyAnswer     = fmt.Errorf("%w: cannot structurize empty answer", ErrBadRequest)
+	ErrNotChannelMember                 = fmt.Errorf("%w: user is not a member of the channel", ErrUserIDMismatch)
+	ErrNotMessageAuthor                 = fmt.Errorf("%w: user is not the author of the message", ErrUserIDMismatch)
+	ErrMessageNotInChannel              = fmt.Errorf("%w: message does not belong to the channel", ErrBadRequest)
+	ErrNoGroupTitle                     = fmt.Errorf("%w: no group channel title", ErrBadRequest)
+	ErrGroupTitleTooLong                = fmt.Errorf("%w: group channel title is too long", ErrBadRequest)
+	ErrNoGroupMembers                   = fmt.Errorf("%w: group channel must have at least one member except owner", ErrBadRequest)
+	ErrGroupMembersLimitExceeded        = fmt.Errorf("%w: group channel members limit exceeded", ErrBadRequest)
+	ErrNotGroupChannel                  = fmt.Errorf("%w: channel is not a group channel", ErrBadRequest)
+	ErrNotChannelAdmin                  = fmt.Errorf("%w: user is not an admin of the channel", ErrUserIDMismatch)
+	ErrCannotRemoveChannelOwner         = fmt.Errorf("%w: cannot remove channel owner", ErrBadRequest)
+	ErrInvalidCursor                    = fmt.Errorf("%w: invalid cursor", ErrBadRequest)
+	ErrBothCursorsProvided              = fmt.Errorf("%w: only one of before and after cursors can be provided", ErrBadRequest)
+	ErrInvalidReaction                  = fmt.Errorf("%w: invalid reaction", ErrBadRequest)
+	ErrReplyMessageNotFound             = fmt.Errorf("%w: message to reply to is not found", ErrBadRequest)
+	ErrEmptySearchQuery                 = fmt.Errorf("%w: empty search query", ErrBadRequest)
+	ErrSearchQueryTooLong               = fmt.Errorf("%w: search query is too long", ErrBadRequest)
+	ErrInvalidReplaySince               = fmt.Errorf("%w: since must be unix timestamp or message id", ErrBadRequest)
+	ErrMessageDeleted                   = fmt.Errorf("%w: message is deleted", ErrBadRequest)
+	ErrInvalidSummaryWindow             = fmt.Errorf("%w: summary window must end after it starts", ErrBadRequest)
+	ErrSummaryWindowTooLong             = fmt.Errorf("%w: summary window is too long", ErrBadRequest)
+	ErrPinnedMessagesLimitExceeded      = fmt.Errorf("%w: pinned messages limit exceeded", ErrBadRequest)
+	ErrInvalidScheduledMessageKind      = fmt.Errorf("%w: invalid scheduled message kind", ErrBadRequest)
+	ErrInvalidScheduleTime              = fmt.Errorf("%w: send_at must be in the future and not later than a year", ErrBadRequest)
+	ErrInvalidSessionTime               = fmt.Errorf("%w: session_at must be set and not earlier than send_at", ErrBadRequest)
+	ErrScheduledMessageNotPending       = fmt.Errorf("%w: scheduled message is already sent or canceled", ErrBadRequest)
+	ErrNothingToSummarize               = fmt.Errorf("%w: no messages in the summary window", ErrBadRequest)
+	ErrUserBlocked                      = fmt.Errorf("%w: user is blocked", ErrUserIDMismatch)
+	ErrInvalidCallSignal                = fmt.Errorf("%w: invalid call signal", ErrInvalidMessage)
+	ErrCallInProgress                   = fmt.Errorf("%w: channel already has a call in progress", ErrBadRequest)
+	ErrCallNotFound                     = fmt.Errorf("%w: call is not found or already finished", ErrBadRequest)
+	ErrCannotAnswerOwnCall              = fmt.Errorf("%w: caller cannot answer own call", ErrBadRequest)
+	ErrMessageCannotBeForwarded         = fmt.Errorf("%w: message of this kind cannot be forwarded", ErrBadRequest)
+	ErrStudyMaterialNotFound            = fmt.Errorf("%w: study material is not found", ErrBadRequest)
+	ErrInvalidExportFormat              = fmt.Errorf("%w: export format must be md, json or html", ErrBadRequest)
 )
@@ -12,5 +12,6 @@ var (
 	ErrMaxRetriesExceeded = errors.New("max retries count exceeded")
 	ErrRequestTimeout     = errors.New("request timed out")
 	ErrInternal           = errors.New("internal error")
+	ErrTooManyRequests    = errors.New("too many requests")
 	ErrInvalidIDType      = fmt.Errorf("%w: the provided hex string is not a valid ObjectID", ErrBadRequest)
 )
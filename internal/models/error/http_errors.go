@@ -10,9 +10,10 @@ import (
 var (
 	ErrorsToHTTPStatusCodes = map[error]int{
 		// common
-		ErrNotFound:     http.StatusNotFound,
-		ErrUnauthorized: http.StatusUnauthorized,
-		ErrBadRequest:   http.StatusBadRequest,
+		ErrNotFound:        http.StatusNotFound,
+		ErrUnauthorized:    http.StatusUnauthorized,
+		ErrBadRequest:      http.StatusBadRequest,
+		ErrTooManyRequests: http.StatusTooManyRequests,
 
 		// auth
 		ErrWrongPassword:      http.StatusBadRequest,
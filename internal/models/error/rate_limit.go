@@ -0,0 +1,27 @@
+package custom_errors
+
+import (
+	"fmt"
+	"time"
+)
+
+var ErrRateLimited = fmt.Errorf("%w: rate limited", ErrTooManyRequests)
+
+// RateLimitedError tells the client when it can retry
+type RateLimitedError struct {
+	RetryAfter time.Duration
+}
+
+func NewRateLimitedError(retryAfter time.Duration) *RateLimitedError {
+	return &RateLimitedError{
+		RetryAfter: retryAfter,
+	}
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrRateLimited.Error(), e.RetryAfter)
+}
+
+func (e *RateLimitedError) Unwrap() error {
+	return ErrRateLimited
+}
@@ -43,4 +43,5 @@ var (
 	// ErrProfanityDetected = &ProfanityError{FieldName: ""}
 	ErrDuplicateReview  = fmt.Errorf("%w: duplicate review", ErrBadRequest)
 	ErrCanNotSelfReview = fmt.Errorf("%w: can not create self-review", ErrBadRequest)
+	ErrCanNotSelfBlock  = fmt.Errorf("%w: can not block yourself", ErrBadRequest)
 )
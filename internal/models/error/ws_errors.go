@@ -0,0 +1,95 @@
+package custom_errors
+
+import (
+	"errors"
+)
+
+const (
+	// ErrCodeInternal is used for errors that are not listed in ErrorsToWSCodes
+	ErrCodeInternal = "internal"
+)
+
+var (
+	// ErrorsToWSCodes are stable codes for websocket error frames, clients should rely on them instead of messages.
+	// The most specific error in the chain wins
+	ErrorsToWSCodes = map[error]string{
+		// common
+		ErrBadRequest:         "bad_request",
+		ErrUnauthorized:       "unauthorized",
+		ErrNotFound:           "not_found",
+		ErrMaxRetriesExceeded: "max_retries_exceeded",
+		ErrRequestTimeout:     "request_timeout",
+		ErrInternal:           ErrCodeInternal,
+		ErrTooManyRequests:    "too_many_requests",
+		ErrRateLimited:        "rate_limited",
+		ErrInvalidIDType:      "invalid_id",
+
+		// auth
+		ErrMissingUserID:  "missing_user_id",
+		ErrUserIDMismatch: "forbidden",
+
+		// chat
+		ErrInvalidMessage:               "invalid_message",
+		ErrInvalidMessageEvent:          "invalid_event",
+		ErrInvalidMessageType:           "invalid_type",
+		ErrBroadcastingTextMessage:      "broadcast_failed",
+		ErrNoChannelID:                  "no_channel_id",
+		ErrNoMessageID:                  "no_message_id",
+		ErrStructurizationUnavailable:   "structurization_unavailable",
+		ErrCannotStructurizeEmptyAnswer: "empty_answer",
+		ErrNotChannelMember:             "not_channel_member",
+		ErrNotMessageAuthor:             "not_message_author",
+		ErrMessageNotInChannel:          "message_not_in_channel",
+		ErrInvalidReaction:              "invalid_reaction",
+		ErrReplyMessageNotFound:         "reply_message_not_found",
+		ErrMessageDeleted:               "message_deleted",
+		ErrPinnedMessagesLimitExceeded:  "pinned_messages_limit_exceeded",
+		ErrUserBlocked:                  "user_blocked",
+		ErrBlockedUsersUnavailable:      "blocked_users_unavailable",
+		ErrInvalidCallSignal:            "invalid_call_signal",
+		ErrCallInProgress:               "call_in_progress",
+		ErrCallNotFound:                 "call_not_found",
+		ErrCannotAnswerOwnCall:          "cannot_answer_own_call",
+		ErrMessageCannotBeForwarded:     "message_cannot_be_forwarded",
+		ErrStudyMaterialNotFound:        "study_material_not_found",
+
+		// file
+		ErrFileNotFound:      "file_not_found",
+		ErrInvalidFileFormat: "invalid_file_format",
+	}
+
+	// retryableErrors are temporary failures, the same frame may succeed later
+	retryableErrors = []error{
+		ErrInternal,
+		ErrRequestTimeout,
+		ErrMaxRetriesExceeded,
+		ErrTooManyRequests,
+		ErrBroadcastingTextMessage,
+		ErrStructurizationUnavailable,
+		ErrBlockedUsersUnavailable,
+	}
+)
+
+// MapErrorToWSCode returns the code of the most specific known error in the chain
+func MapErrorToWSCode(err error) string {
+	for currentErr := err; currentErr != nil; currentErr = errors.Unwrap(currentErr) {
+		if code, ok := ErrorsToWSCodes[currentErr]; ok {
+			return code
+		}
+	}
+	return ErrCodeInternal
+}
+
+// IsRetryableError reports whether the client may retry the request.
+// Unknown errors are treated as internal, so they are retryable
+func IsRetryableError(err error) bool {
+	if MapErrorToWSCode(err) == ErrCodeInternal {
+		return true
+	}
+	for _, retryableErr := range retryableErrors {
+		if errors.Is(err, retryableErr) {
+			return true
+		}
+	}
+	return false
+}
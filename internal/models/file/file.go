@@ -3,3 +3,14 @@ package filemodels
 type File struct {
 	Filename string `json:"filename"`
 }
+
+// AttachmentMetadata is collected when a temp file becomes an attachment.
+// Width and Height are set only for images
+type AttachmentMetadata struct {
+	Filename     string
+	OriginalName string
+	Size         int64
+	MimeType     string
+	Width        int
+	Height       int
+}
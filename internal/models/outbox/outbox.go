@@ -0,0 +1,35 @@
+package outbox_models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type EntryStatus string
+
+const (
+	PendingEntryStatus = EntryStatus("pending")
+	SentEntryStatus    = EntryStatus("sent")
+	FailedEntryStatus  = EntryStatus("failed")
+
+	// KeyMetadata of the published message is saved as Entry.Key
+	KeyMetadata = "outbox_key"
+)
+
+// Entry is a broker message saved to mongo to be published by the relay.
+// Entries with the same Key (e.g. events of one channel) are published in the order they were saved
+type Entry struct {
+	ID            bson.ObjectID     `bson:"_id,omitempty"`
+	UUID          string            `bson:"uuid"`
+	Topic         string            `bson:"topic"`
+	Key           string            `bson:"key,omitempty"`
+	Payload       []byte            `bson:"payload"`
+	Metadata      map[string]string `bson:"metadata,omitempty"`
+	Status        EntryStatus       `bson:"status"`
+	Attempts      int               `bson:"attempts"`
+	LastError     string            `bson:"last_error,omitempty"`
+	NextAttemptAt time.Time         `bson:"next_attempt_at"`
+	CreatedAt     time.Time         `bson:"created_at"`
+	SentAt        *time.Time        `bson:"sent_at,omitempty"`
+}
@@ -4,7 +4,20 @@ type StructurizedMessage struct {
 	Explanation string `json:"explanation"`
 }
 
+const (
+	// SummaryMode asks the explanator to summarize the conversation passed as answer
+	SummaryMode = "summary"
+)
+
 type StructRequest struct {
 	Query  string `json:"query"`
 	Answer string `json:"answer"`
+	Mode   string `json:"mode,omitempty"`
+}
+
+// CachedStructurization is an explanation reused for equal question and answer in any message
+type CachedStructurization struct {
+	Hash         string `bson:"_id"`
+	Structurized string `bson:"structurized"`
+	CreatedAt    int64  `bson:"created_at"`
 }
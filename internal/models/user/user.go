@@ -24,6 +24,7 @@ type User struct {
 	Hrefs           []string      `json:"hrefs" bson:"hrefs"`
 	Rating          float64       `json:"rating" bson:"-"`
 	Reviews         []*Review     `json:"reviews" bson:"-"`
+	BlockedUserIDs  []string      `json:"-" bson:"blocked_user_ids,omitempty"`
 }
 
 type UserToCreate struct {
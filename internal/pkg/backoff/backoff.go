@@ -0,0 +1,13 @@
+package backoff
+
+import "time"
+
+// Exponential returns delay before the next retry: minBackoff after the first attempt,
+// doubled after every next one and capped by maxBackoff
+func Exponential(attempts int, minBackoff, maxBackoff time.Duration) time.Duration {
+	backoff := minBackoff
+	for i := 1; i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxBackoff)
+}
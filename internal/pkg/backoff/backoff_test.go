@@ -0,0 +1,30 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExponential(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts int
+		min, max time.Duration
+		want     time.Duration
+	}{
+		{name: "no attempts yet", attempts: 0, min: 5 * time.Second, max: 5 * time.Minute, want: 5 * time.Second},
+		{name: "first attempt", attempts: 1, min: 5 * time.Second, max: 5 * time.Minute, want: 5 * time.Second},
+		{name: "second attempt", attempts: 2, min: 5 * time.Second, max: 5 * time.Minute, want: 10 * time.Second},
+		{name: "fifth attempt", attempts: 5, min: 5 * time.Second, max: 5 * time.Minute, want: 80 * time.Second},
+		{name: "capped", attempts: 7, min: 5 * time.Second, max: 5 * time.Minute, want: 5 * time.Minute},
+		{name: "many attempts do not overflow", attempts: 1000, min: 5 * time.Second, max: 5 * time.Minute, want: 5 * time.Minute},
+		{name: "min above max", attempts: 1, min: time.Minute, max: time.Second, want: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Exponential(tt.attempts, tt.min, tt.max); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -9,16 +9,26 @@ type HTTP struct {
 }
 
 type ChatServices struct {
-	UserService            *GRPCService `mapstructure:"user_service"`
-	StructurizationService *GRPCService `mapstructure:"structurization_service"`
-	FileService            *GRPCService `mapstructure:"file_service"`
+	UserService          *GRPCService `mapstructure:"user_service"`
+	FileService          *GRPCService `mapstructure:"file_service"`
+	AuthService          *GRPCService `mapstructure:"auth_service"`
+	StudyMaterialService *GRPCService `mapstructure:"study_material_service"`
+}
+
+// ChatExport configures history export, attachment links are StaticURL/<bucket>/<filename>
+type ChatExport struct {
+	StaticURL string `mapstructure:"static_url"`
 }
 
 type Chat struct {
-	HTTP     *HTTP         `mapstructure:"http"`
-	Services *ChatServices `mapstructure:"services"`
-	Mongo    *Mongo        `mapstructure:"mongo"`
-	Kafka    *Kafka        `mapstructure:"kafka"`
+	HTTP       *HTTP           `mapstructure:"http"`
+	Services   *ChatServices   `mapstructure:"services"`
+	Mongo      *Mongo          `mapstructure:"mongo"`
+	Kafka      *Kafka          `mapstructure:"kafka"`
+	Redis      *Redis          `mapstructure:"redis"`
+	Outbox     *Outbox         `mapstructure:"outbox"`
+	RateLimits *ChatRateLimits `mapstructure:"rate_limits"`
+	Export     *ChatExport     `mapstructure:"export"`
 }
 
 func NewChat() (*Chat, error) {
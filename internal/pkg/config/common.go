@@ -7,4 +7,6 @@ const (
 	AuthConfigPath              = "/app/dev/config/auth_config.yaml"
 	StudyMaterialConfigPath     = "/app/dev/config/study_material_config.yaml"
 	VoiceRecognitionDConfigPath = "/app/dev/config/voicerecognitiond_config.yaml"
+	StructurizationDConfigPath  = "/app/dev/config/structurizationd_config.yaml"
+	SchedulerDConfigPath        = "/app/dev/config/schedulerd_config.yaml"
 )
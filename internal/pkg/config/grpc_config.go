@@ -2,6 +2,8 @@ package config
 
 type GRPC struct {
 	Port int `mapstructure:"port"`
+	// ServiceToken is required from callers of methods available only to internal services
+	ServiceToken string `mapstructure:"service_token"`
 }
 
 type GRPCService struct {
@@ -9,4 +11,6 @@ type GRPCService struct {
 	Port       int    `mapstructure:"port"`
 	Timeout    int    `mapstructure:"timeout"`
 	MaxRetries int    `mapstructure:"max_retries"`
+	// Token is sent to the service to call its internal methods
+	Token string `mapstructure:"token"`
 }
@@ -3,7 +3,10 @@ package config
 type Kafka struct {
 	Version                             string   `mapstructure:"version"`
 	Addresses                           []string `mapstructure:"addresses"`
+	ConsumerGroup                       string   `mapstructure:"consumer_group,omitempty"`
 	StudyMaterialTopic                  string   `mapstructure:"study_material_topic,omitempty"`
 	VoiceRecognitionNewVoiceTopic       string   `mapstructure:"voice_recognition_new_voice_topic,omitempty"`
 	VoiceRecognitionVoiceProcessedTopic string   `mapstructure:"voice_recognition_voice_processed_topic,omitempty"`
+	StructurizationRequestTopic         string   `mapstructure:"structurization_request_topic,omitempty"`
+	SummaryRequestTopic                 string   `mapstructure:"summary_request_topic,omitempty"`
 }
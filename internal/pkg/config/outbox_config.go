@@ -0,0 +1,7 @@
+package config
+
+type Outbox struct {
+	PollIntervalMs int `mapstructure:"poll_interval_ms"`
+	BatchSize      int `mapstructure:"batch_size"`
+	MaxAttempts    int `mapstructure:"max_attempts"`
+}
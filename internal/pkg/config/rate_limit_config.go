@@ -0,0 +1,17 @@
+package config
+
+// RateLimit is a token bucket: Rate tokens are added per second up to Burst
+type RateLimit struct {
+	Rate  float64 `mapstructure:"rate"`
+	Burst int     `mapstructure:"burst"`
+}
+
+type ChatRateLimits struct {
+	Text            *RateLimit `mapstructure:"text"`
+	Voice           *RateLimit `mapstructure:"voice"`
+	Structurization *RateLimit `mapstructure:"structurization"`
+	// Call is used for signaling, ICE candidates come in bursts when a call starts
+	Call *RateLimit `mapstructure:"call"`
+	// Other is used for the rest of the events: typing, reactions, read marks etc.
+	Other *RateLimit `mapstructure:"other"`
+}
@@ -0,0 +1,37 @@
+package config
+
+import "github.com/spf13/viper"
+
+type Scheduler struct {
+	PollIntervalMs int `mapstructure:"poll_interval_ms"`
+	BatchSize      int `mapstructure:"batch_size"`
+	MaxAttempts    int `mapstructure:"max_attempts"`
+}
+
+type SchedulerDServices struct {
+	UserService *GRPCService `mapstructure:"user_service"`
+}
+
+type SchedulerD struct {
+	Mongo     *Mongo              `mapstructure:"mongo"`
+	Kafka     *Kafka              `mapstructure:"kafka"`
+	Outbox    *Outbox             `mapstructure:"outbox"`
+	Scheduler *Scheduler          `mapstructure:"scheduler"`
+	Services  *SchedulerDServices `mapstructure:"services"`
+}
+
+func NewSchedulerD() (*SchedulerD, error) {
+	v := viper.New()
+	v.AutomaticEnv()
+	v.SetConfigFile(SchedulerDConfigPath)
+	err := v.ReadInConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg := &SchedulerD{}
+	err = v.Unmarshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
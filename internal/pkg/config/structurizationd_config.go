@@ -0,0 +1,29 @@
+package config
+
+import "github.com/spf13/viper"
+
+type StructurizationDServices struct {
+	StructurizationService *GRPCService `mapstructure:"structurization_service"`
+}
+
+type StructurizationD struct {
+	Mongo    *Mongo                    `mapstructure:"mongo"`
+	Kafka    *Kafka                    `mapstructure:"kafka"`
+	Services *StructurizationDServices `mapstructure:"services"`
+}
+
+func NewStructurizationD() (*StructurizationD, error) {
+	v := viper.New()
+	v.AutomaticEnv()
+	v.SetConfigFile(StructurizationDConfigPath)
+	err := v.ReadInConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg := &StructurizationD{}
+	err = v.Unmarshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
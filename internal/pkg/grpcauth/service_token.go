@@ -0,0 +1,58 @@
+package grpcauth
+
+import (
+	"context"
+	"crypto/subtle"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceTokenKey is the metadata key internal services pass their token in
+const ServiceTokenKey = "x-service-token"
+
+// ServiceTokenClientInterceptor adds the token of the calling service to every call
+func ServiceTokenClientInterceptor(token string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if token != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, ServiceTokenKey, token)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// ServiceTokenServerInterceptor lets into methods only callers with the token, other methods are not checked.
+// Without configured token the methods are closed for everyone
+func ServiceTokenServerInterceptor(token string, methods ...string) grpc.UnaryServerInterceptor {
+	restricted := make(map[string]struct{}, len(methods))
+	for _, method := range methods {
+		restricted[method] = struct{}{}
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, ok := restricted[info.FullMethod]; !ok {
+			return handler(ctx, req)
+		}
+		if !hasServiceToken(ctx, token) {
+			return nil, status.Errorf(codes.PermissionDenied, "%s is available only to internal services", info.FullMethod)
+		}
+		return handler(ctx, req)
+	}
+}
+
+func hasServiceToken(ctx context.Context, token string) bool {
+	if token == "" {
+		return false
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	for _, value := range md.Get(ServiceTokenKey) {
+		if subtle.ConstantTimeCompare([]byte(value), []byte(token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -24,3 +24,15 @@ func GetLimitAndOffset(c *gin.Context) (limit int64, offset int64) {
 
 	return limit, offset
 }
+
+// GetCursorsAndLimit parses cursor pagination params. Cursors are returned as is, empty if not provided
+func GetCursorsAndLimit(c *gin.Context) (before string, after string, limit int64) {
+	limit, err := strconv.ParseInt(strings.TrimSpace(c.Query("limit")), 10, 64)
+	if err != nil || limit <= 0 {
+		limit = defaultLimit
+	}
+	before = strings.TrimSpace(c.Query("before"))
+	after = strings.TrimSpace(c.Query("after"))
+
+	return before, after, limit
+}
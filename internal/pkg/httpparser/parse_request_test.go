@@ -0,0 +1,69 @@
+package httpparser
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetCursorsAndLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantBefore string
+		wantAfter  string
+		wantLimit  int64
+	}{
+		{
+			name:      "no params",
+			query:     "",
+			wantLimit: defaultLimit,
+		},
+		{
+			name:       "before cursor",
+			query:      "?before=MTc6aWQ&limit=50",
+			wantBefore: "MTc6aWQ",
+			wantLimit:  50,
+		},
+		{
+			name:      "after cursor is trimmed",
+			query:     "?after=%20MTc6aWQ%20",
+			wantAfter: "MTc6aWQ",
+			wantLimit: defaultLimit,
+		},
+		{
+			name:       "both cursors are returned as is",
+			query:      "?before=b&after=a",
+			wantBefore: "b",
+			wantAfter:  "a",
+			wantLimit:  defaultLimit,
+		},
+		{
+			name:      "negative limit",
+			query:     "?limit=-5",
+			wantLimit: defaultLimit,
+		},
+		{
+			name:      "zero limit",
+			query:     "?limit=0",
+			wantLimit: defaultLimit,
+		},
+		{
+			name:      "limit is not a number",
+			query:     "?limit=ten",
+			wantLimit: defaultLimit,
+		},
+	}
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/messages"+tt.query, nil)
+			before, after, limit := GetCursorsAndLimit(c)
+			if before != tt.wantBefore || after != tt.wantAfter || limit != tt.wantLimit {
+				t.Fatalf("got (%q, %q, %d), want (%q, %q, %d)", before, after, limit, tt.wantBefore, tt.wantAfter, tt.wantLimit)
+			}
+		})
+	}
+}
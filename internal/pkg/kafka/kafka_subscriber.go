@@ -2,18 +2,23 @@ package infrakafka
 
 import (
 	"errors"
+	"fmt"
 	"github.com/IBM/sarama"
 	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/config"
-	"github.com/ThreeDotsLabs/watermill"
 	"github.com/ThreeDotsLabs/watermill-kafka/v3/pkg/kafka"
 	"github.com/ThreeDotsLabs/watermill/message"
 	"time"
 )
 
-func NewKafkaSubscriber(cfg *config.Kafka) (message.Subscriber, error) {
+// NewKafkaSubscriber creates subscriber in the consumer group. Subscribers of the same group share messages of the topic,
+// so daemons use a common group, and each of them processes only a part of messages
+func NewKafkaSubscriber(cfg *config.Kafka, consumerGroup string) (message.Subscriber, error) {
 	if cfg == nil {
 		return nil, errors.New("kafka subscriber config is nil")
 	}
+	if consumerGroup == "" {
+		return nil, errors.New("kafka consumer group is empty")
+	}
 	saramaConfig := sarama.NewConfig()
 	saramaVersion, err := sarama.ParseKafkaVersion(cfg.Version)
 	if err != nil {
@@ -28,7 +33,7 @@ func NewKafkaSubscriber(cfg *config.Kafka) (message.Subscriber, error) {
 		kafka.SubscriberConfig{
 			Brokers:       cfg.Addresses,
 			Unmarshaler:   kafka.DefaultMarshaler{},
-			ConsumerGroup: watermill.NewUUID(),
+			ConsumerGroup: consumerGroup,
 			InitializeTopicDetails: &sarama.TopicDetail{
 				NumPartitions:     1,
 				ReplicationFactor: 2,
@@ -43,3 +48,9 @@ func NewKafkaSubscriber(cfg *config.Kafka) (message.Subscriber, error) {
 
 	return kafkaSubscriber, nil
 }
+
+// InstanceConsumerGroup returns a consumer group unique for this process,
+// so its subscriber gets all messages of the topic, e.g. to deliver them to local websocket sessions
+func InstanceConsumerGroup(consumerGroup, instanceID string) string {
+	return fmt.Sprintf("%s.%s", consumerGroup, instanceID)
+}
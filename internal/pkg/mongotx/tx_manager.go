@@ -0,0 +1,86 @@
+package mongotx
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// TxManager runs a function in a mongo transaction.
+// Repositories join the transaction when called with the ctx passed to fn
+type TxManager interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type TxManagerImpl struct {
+	client *mongo.Client
+}
+
+func NewTxManager(client *mongo.Client) TxManager {
+	return &TxManagerImpl{
+		client: client,
+	}
+}
+
+type afterCommitKey struct{}
+
+// afterCommitHooks are registered by fn and run once the transaction is committed
+type afterCommitHooks struct {
+	mu    sync.Mutex
+	hooks []func()
+}
+
+func (h *afterCommitHooks) add(hook func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.hooks = append(h.hooks, hook)
+}
+
+func (h *afterCommitHooks) reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.hooks = nil
+}
+
+func (h *afterCommitHooks) run() {
+	h.mu.Lock()
+	hooks := h.hooks
+	h.hooks = nil
+	h.mu.Unlock()
+	for _, hook := range hooks {
+		hook()
+	}
+}
+
+// WithTransaction commits if fn returns nil. fn may be retried on transient errors, so it must not have side effects outside mongo,
+// such effects are registered with AfterCommit
+func (t *TxManagerImpl) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	sess, err := t.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer sess.EndSession(ctx)
+
+	hooks := &afterCommitHooks{}
+	_, err = sess.WithTransaction(ctx, func(ctx context.Context) (any, error) {
+		// hooks of an aborted attempt are dropped, the retry registers them again
+		hooks.reset()
+		return nil, fn(context.WithValue(ctx, afterCommitKey{}, hooks))
+	})
+	if err != nil {
+		return err
+	}
+	hooks.run()
+	return nil
+}
+
+// AfterCommit runs hook after the transaction of ctx is committed, or right away if ctx has no transaction
+func AfterCommit(ctx context.Context, hook func()) {
+	hooks, ok := ctx.Value(afterCommitKey{}).(*afterCommitHooks)
+	if !ok {
+		hook()
+		return
+	}
+	hooks.add(hook)
+}
@@ -3,6 +3,7 @@ package channelrepo
 import (
 	"context"
 	"errors"
+	"fmt"
 	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
 	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
 	"github.com/rs/zerolog"
@@ -17,6 +18,17 @@ type ChannelRepository interface {
 	GetChannelByID(ctx context.Context, id string) (chat_models.Channel, error)
 	GetChannelsByUserID(ctx context.Context, userID string, limit, offset int64) ([]chat_models.Channel, error)
 	GetByUserIDs(ctx context.Context, userIDs []string) (chat_models.Channel, error)
+	UpdateChannel(ctx context.Context, channel chat_models.Channel) error
+	AddMembers(ctx context.Context, id string, userIDs []string, maxMembers int, updated int64) (chat_models.Channel, error)
+	RemoveMember(ctx context.Context, id string, userID string, updated int64) (chat_models.Channel, error)
+	PinMessage(ctx context.Context, id, messageID string, maxPinned int) (chat_models.Channel, error)
+	UnpinMessage(ctx context.Context, id, messageID string) (chat_models.Channel, error)
+	MuteChannel(ctx context.Context, id, userID string) (chat_models.Channel, error)
+	UnmuteChannel(ctx context.Context, id, userID string) (chat_models.Channel, error)
+	StartCall(ctx context.Context, id string, call chat_models.CallState, staleRingingBefore, staleActiveBefore int64) (chat_models.Channel, error)
+	UpdateCallStatus(ctx context.Context, id, callID, fromStatus, status string, at int64) (chat_models.Channel, error)
+	GetChannelsWithStaleCalls(ctx context.Context, staleRingingBefore, staleActiveBefore, limit int64) ([]chat_models.Channel, error)
+	EnsureIndexes(ctx context.Context) error
 }
 
 type ChannelRepositoryImpl struct {
@@ -31,9 +43,21 @@ func NewChannelRepository(mongoDB *mongo.Collection, logger zerolog.Logger) Chan
 	}
 }
 
+// EnsureIndexes creates indexes required by the stale calls sweep, it is safe to call on every startup
+func (r *ChannelRepositoryImpl) EnsureIndexes(ctx context.Context) error {
+	_, err := r.mongoDB.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "call.status", Value: 1}},
+		Options: options.Index().SetName("call_status"),
+	})
+	return err
+}
+
 func (r *ChannelRepositoryImpl) InsertChannel(ctx context.Context, channel chat_models.Channel) (chat_models.Channel, error) {
-	// sort userIDs for speeding up the search by user_ids, as mongo stores arrays in stable order
-	slices.Sort(channel.UserIDs)
+	// sort userIDs for speeding up the search by user_ids, as mongo stores arrays in stable order.
+	// group channels are never searched by exact members, so their order (owner first) is kept
+	if !channel.IsGroup() {
+		slices.Sort(channel.UserIDs)
+	}
 	res, err := r.mongoDB.InsertOne(ctx, channel)
 	if err != nil {
 		return channel, err
@@ -110,6 +134,10 @@ func (r *ChannelRepositoryImpl) GetByUserIDs(ctx context.Context, userIDs []stri
 		"user_ids": bson.M{
 			"$eq": userIDs,
 		},
+		// group channels may have the same members, but they are never direct conversations
+		"type": bson.M{
+			"$ne": chat_models.GroupChannelType,
+		},
 	})
 	curr := chat_models.BSONChannel{}
 	err := res.Decode(&curr)
@@ -123,3 +151,326 @@ func (r *ChannelRepositoryImpl) GetByUserIDs(ctx context.Context, userIDs []stri
 
 	return channel, nil
 }
+
+func (r *ChannelRepositoryImpl) UpdateChannel(ctx context.Context, channel chat_models.Channel) error {
+	objID, err := bson.ObjectIDFromHex(channel.ID)
+	if err != nil {
+		return err
+	}
+	res, err := r.mongoDB.UpdateByID(ctx, objID, bson.M{
+		"$set": bson.M{
+			"title":     channel.Title,
+			"owner_id":  channel.OwnerID,
+			"admin_ids": channel.AdminIDs,
+			"updated":   channel.Updated,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return custom_errors.ErrNotFound
+	}
+
+	return nil
+}
+
+// AddMembers adds users to the channel if it has room for all of them after the add.
+// The limit is checked in the same update, so concurrent invites can't exceed maxMembers
+func (r *ChannelRepositoryImpl) AddMembers(ctx context.Context, id string, userIDs []string, maxMembers int, updated int64) (chat_models.Channel, error) {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return chat_models.Channel{}, err
+	}
+	if len(userIDs) > maxMembers {
+		return chat_models.Channel{}, custom_errors.ErrGroupMembersLimitExceeded
+	}
+	res := r.mongoDB.FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"_id": objID,
+			// user_ids has at most maxMembers-len(userIDs) elements
+			fmt.Sprintf("user_ids.%d", maxMembers-len(userIDs)): bson.M{
+				"$exists": false,
+			},
+		},
+		bson.M{
+			"$addToSet": bson.M{
+				"user_ids": bson.M{
+					"$each": userIDs,
+				},
+			},
+			"$set": bson.M{
+				"updated": updated,
+			},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+	curr := chat_models.BSONChannel{}
+	err = res.Decode(&curr)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// the channel is checked by the caller, so it is full
+			return chat_models.Channel{}, custom_errors.ErrGroupMembersLimitExceeded
+		}
+		return chat_models.Channel{}, err
+	}
+
+	return curr.ToChannel(), nil
+}
+
+func (r *ChannelRepositoryImpl) RemoveMember(ctx context.Context, id string, userID string, updated int64) (chat_models.Channel, error) {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return chat_models.Channel{}, err
+	}
+	return r.findOneAndUpdate(ctx, objID, bson.M{
+		"$pull": bson.M{
+			"user_ids":       userID,
+			"admin_ids":      userID,
+			"muted_user_ids": userID,
+		},
+		"$set": bson.M{
+			"updated": updated,
+		},
+	})
+}
+
+// PinMessage adds the message to pinned ones if there are less than maxPinned of them.
+// Pinning already pinned message is a no-op
+func (r *ChannelRepositoryImpl) PinMessage(ctx context.Context, id, messageID string, maxPinned int) (chat_models.Channel, error) {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return chat_models.Channel{}, err
+	}
+	res := r.mongoDB.FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"_id": objID,
+			"$or": bson.A{
+				bson.M{
+					fmt.Sprintf("pinned_message_ids.%d", maxPinned-1): bson.M{
+						"$exists": false,
+					},
+				},
+				bson.M{
+					"pinned_message_ids": messageID,
+				},
+			},
+		},
+		bson.M{
+			"$addToSet": bson.M{
+				"pinned_message_ids": messageID,
+			},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+	curr := chat_models.BSONChannel{}
+	err = res.Decode(&curr)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// the channel is checked by the caller, so the limit is the only reason
+			return chat_models.Channel{}, custom_errors.ErrPinnedMessagesLimitExceeded
+		}
+		return chat_models.Channel{}, err
+	}
+
+	return curr.ToChannel(), nil
+}
+
+func (r *ChannelRepositoryImpl) UnpinMessage(ctx context.Context, id, messageID string) (chat_models.Channel, error) {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return chat_models.Channel{}, err
+	}
+	return r.findOneAndUpdate(ctx, objID, bson.M{
+		"$pull": bson.M{
+			"pinned_message_ids": messageID,
+		},
+	})
+}
+
+func (r *ChannelRepositoryImpl) MuteChannel(ctx context.Context, id, userID string) (chat_models.Channel, error) {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return chat_models.Channel{}, err
+	}
+	return r.findOneAndUpdate(ctx, objID, bson.M{
+		"$addToSet": bson.M{
+			"muted_user_ids": userID,
+		},
+	})
+}
+
+func (r *ChannelRepositoryImpl) UnmuteChannel(ctx context.Context, id, userID string) (chat_models.Channel, error) {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return chat_models.Channel{}, err
+	}
+	return r.findOneAndUpdate(ctx, objID, bson.M{
+		"$pull": bson.M{
+			"muted_user_ids": userID,
+		},
+	})
+}
+
+// StartCall sets the call of the channel if the previous one is finished.
+// Calls which are ringing or active since before stale* (e.g. clients crashed without ending them) are replaced too
+func (r *ChannelRepositoryImpl) StartCall(ctx context.Context, id string, call chat_models.CallState, staleRingingBefore, staleActiveBefore int64) (chat_models.Channel, error) {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return chat_models.Channel{}, err
+	}
+	res := r.mongoDB.FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"_id": objID,
+			"$or": bson.A{
+				bson.M{
+					"call": bson.M{
+						"$exists": false,
+					},
+				},
+				bson.M{
+					"call.status": bson.M{
+						"$in": bson.A{chat_models.CallEndedStatus, chat_models.CallMissedStatus},
+					},
+				},
+				bson.M{
+					"call.status":     chat_models.CallRingingStatus,
+					"call.started_at": bson.M{"$lt": staleRingingBefore},
+				},
+				bson.M{
+					"call.status":      chat_models.CallActiveStatus,
+					"call.answered_at": bson.M{"$lt": staleActiveBefore},
+				},
+			},
+		},
+		bson.M{
+			"$set": bson.M{
+				"call": call,
+			},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+	curr := chat_models.BSONChannel{}
+	err = res.Decode(&curr)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// the channel is checked by the caller, so there is an unfinished call
+			return chat_models.Channel{}, custom_errors.ErrCallInProgress
+		}
+		return chat_models.Channel{}, err
+	}
+
+	return curr.ToChannel(), nil
+}
+
+// UpdateCallStatus moves the call from fromStatus to status.
+// at is saved as answered_at for the active status and as ended_at for the rest
+func (r *ChannelRepositoryImpl) UpdateCallStatus(ctx context.Context, id, callID, fromStatus, status string, at int64) (chat_models.Channel, error) {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return chat_models.Channel{}, err
+	}
+	atField := "call.ended_at"
+	if status == chat_models.CallActiveStatus {
+		atField = "call.answered_at"
+	}
+	res := r.mongoDB.FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"_id":          objID,
+			"call.call_id": callID,
+			"call.status":  fromStatus,
+		},
+		bson.M{
+			"$set": bson.M{
+				"call.status": status,
+				atField:       at,
+			},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+	curr := chat_models.BSONChannel{}
+	err = res.Decode(&curr)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// the call was already answered or ended concurrently
+			return chat_models.Channel{}, custom_errors.ErrCallNotFound
+		}
+		return chat_models.Channel{}, err
+	}
+
+	return curr.ToChannel(), nil
+}
+
+// GetChannelsWithStaleCalls returns channels with calls ringing since before staleRingingBefore
+// or active since before staleActiveBefore, oldest calls first
+func (r *ChannelRepositoryImpl) GetChannelsWithStaleCalls(ctx context.Context, staleRingingBefore, staleActiveBefore, limit int64) ([]chat_models.Channel, error) {
+	cur, err := r.mongoDB.Find(
+		ctx,
+		bson.M{
+			"$or": bson.A{
+				bson.M{
+					"call.status":     chat_models.CallRingingStatus,
+					"call.started_at": bson.M{"$lt": staleRingingBefore},
+				},
+				bson.M{
+					"call.status":      chat_models.CallActiveStatus,
+					"call.answered_at": bson.M{"$lt": staleActiveBefore},
+				},
+			},
+		},
+		options.Find().SetSort(
+			bson.M{
+				"call.started_at": 1,
+			},
+		).SetLimit(limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cur.Close(ctx)
+		if err != nil {
+			r.logger.Err(err)
+			return
+		}
+	}()
+	res := make([]chat_models.Channel, 0, cur.RemainingBatchLength())
+	for cur.Next(ctx) {
+		curr := chat_models.BSONChannel{}
+		err = cur.Decode(&curr)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, curr.ToChannel())
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *ChannelRepositoryImpl) findOneAndUpdate(ctx context.Context, objID bson.ObjectID, update bson.M) (chat_models.Channel, error) {
+	res := r.mongoDB.FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"_id": objID,
+		},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+	curr := chat_models.BSONChannel{}
+	err := res.Decode(&curr)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return chat_models.Channel{}, custom_errors.ErrNotFound
+		}
+		return chat_models.Channel{}, err
+	}
+
+	return curr.ToChannel(), nil
+}
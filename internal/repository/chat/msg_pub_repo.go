@@ -3,6 +3,7 @@ package message_repo
 import (
 	"context"
 	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	outbox_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/outbox"
 	"github.com/ThreeDotsLabs/watermill"
 	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/rs/zerolog"
@@ -14,22 +15,39 @@ const (
 
 type MessagePubRepo interface {
 	PublishMessage(ctx context.Context, msg chat_models.Message) error
+	PublishEphemeral(ctx context.Context, msg chat_models.Message) error
 }
 
 type MessagePubRepoImpl struct {
-	p      message.Publisher
-	logger zerolog.Logger
+	p          message.Publisher
+	ephemeralP message.Publisher
+	logger     zerolog.Logger
 }
 
-func NewMessagePubRepo(p message.Publisher, logger zerolog.Logger) MessagePubRepo {
+// NewMessagePubRepo creates repo publishing messages with p (the outbox) and
+// short-lived events (typing, presence) directly with ephemeralP - they are useless when delayed
+func NewMessagePubRepo(p message.Publisher, ephemeralP message.Publisher, logger zerolog.Logger) MessagePubRepo {
 	return &MessagePubRepoImpl{
-		p:      p,
-		logger: logger,
+		p:          p,
+		ephemeralP: ephemeralP,
+		logger:     logger,
 	}
 }
 
-func (m *MessagePubRepoImpl) PublishMessage(_ context.Context, msg chat_models.Message) error {
-	return m.p.Publish(MessagePubTopic, message.NewMessage(
+func (m *MessagePubRepoImpl) PublishMessage(ctx context.Context, msg chat_models.Message) error {
+	wmMsg := message.NewMessage(
+		watermill.NewUUID(),
+		msg.Encode(),
+	)
+	// outbox uses the context to join the transaction
+	wmMsg.SetContext(ctx)
+	// events of a channel are delivered in the order they happened
+	wmMsg.Metadata.Set(outbox_models.KeyMetadata, msg.ChannelID)
+	return m.p.Publish(MessagePubTopic, wmMsg)
+}
+
+func (m *MessagePubRepoImpl) PublishEphemeral(_ context.Context, msg chat_models.Message) error {
+	return m.ephemeralP.Publish(MessagePubTopic, message.NewMessage(
 		watermill.NewUUID(),
 		msg.Encode(),
 	))
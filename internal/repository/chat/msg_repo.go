@@ -9,15 +9,32 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"slices"
+)
+
+const (
+	// only the latest explanations are kept, users flip between them
+	maxStructurizedVersions = 10
 )
 
 type MessageRepo interface {
-	GetMessagesByChannelID(ctx context.Context, channelID string, limit, offset int64) ([]chat_models.Message, error)
+	EnsureIndexes(ctx context.Context) error
+	GetMessagesByChannelID(ctx context.Context, channelID string, query chat_models.MessagesQuery) ([]chat_models.Message, error)
+	SearchMessages(ctx context.Context, channelIDs []string, text string, query chat_models.MessagesQuery) ([]chat_models.Message, error)
+	GetMessagesChangedSince(ctx context.Context, channelIDs []string, since, limit int64) ([]chat_models.Message, error)
+	CountUnreadMessages(ctx context.Context, channelID, userID string, lastRead *chat_models.MessageCursor) (int64, error)
 	GetPreviousMessagesByMessageCreatedAt(ctx context.Context, channelID string, createdAt, limit int64) ([]chat_models.Message, error)
+	GetMessagesInWindow(ctx context.Context, channelID string, from, to, limit int64) ([]chat_models.Message, error)
+	ForEachChannelMessage(ctx context.Context, channelID string, fn func(msg chat_models.Message) error) error
 	GetMessageByID(ctx context.Context, id string) (*chat_models.Message, error)
+	GetMessagesByIDs(ctx context.Context, ids []string) ([]chat_models.Message, error)
 	InsertMessage(ctx context.Context, msg chat_models.Message) (chat_models.Message, error)
 	UpdateMessage(ctx context.Context, msg chat_models.Message) error
-	DeleteMessage(ctx context.Context, msg chat_models.Message) error
+	EditMessage(ctx context.Context, msg chat_models.Message, revision chat_models.MessageRevision) error
+	DeleteMessage(ctx context.Context, tombstone chat_models.Message) error
+	AddReaction(ctx context.Context, messageID, reaction, userID string, updatedAt int64) (*chat_models.Message, error)
+	RemoveReaction(ctx context.Context, messageID, reaction, userID string, updatedAt int64) (*chat_models.Message, error)
+	SetStructurized(ctx context.Context, messageID string, versions []chat_models.StructurizedVersion, updatedAt int64) (*chat_models.Message, error)
 }
 
 type MessageRepoImpl struct {
@@ -32,6 +49,48 @@ func NewMessageRepo(mongoDB *mongo.Collection, logger zerolog.Logger) MessageRep
 	}
 }
 
+// EnsureIndexes creates indexes required by the history queries, it is safe to call on every startup
+func (m *MessageRepoImpl) EnsureIndexes(ctx context.Context) error {
+	_, err := m.mongoDB.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "channel_id", Value: 1},
+				{Key: "created_at", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+			Options: options.Index().SetName("channel_id_created_at_id"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "channel_id", Value: 1},
+				{Key: "updated_at", Value: 1},
+			},
+			Options: options.Index().SetName("channel_id_updated_at"),
+		},
+		{
+			// collection can have only one text index, every searchable field goes here
+			Keys: bson.D{
+				{Key: "payload", Value: "text"},
+				{Key: "recognized_voice", Value: "text"},
+				{Key: "structurized", Value: "text"},
+			},
+			Options: options.Index().
+				SetName("messages_text").
+				SetDefaultLanguage("russian").
+				SetWeights(bson.D{
+					{Key: "payload", Value: 3},
+					{Key: "recognized_voice", Value: 2},
+					{Key: "structurized", Value: 1},
+				}),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (m *MessageRepoImpl) GetMessageByID(ctx context.Context, id string) (*chat_models.Message, error) {
 	objID, err := bson.ObjectIDFromHex(id)
 	if err != nil {
@@ -53,6 +112,50 @@ func (m *MessageRepoImpl) GetMessageByID(ctx context.Context, id string) (*chat_
 	return &msg, nil
 }
 
+// GetMessagesByIDs returns found messages in no particular order, invalid and missing ids are skipped
+func (m *MessageRepoImpl) GetMessagesByIDs(ctx context.Context, ids []string) ([]chat_models.Message, error) {
+	objIDs := make([]bson.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		objID, err := bson.ObjectIDFromHex(id)
+		if err != nil {
+			continue
+		}
+		objIDs = append(objIDs, objID)
+	}
+	if len(objIDs) == 0 {
+		return []chat_models.Message{}, nil
+	}
+	cur, err := m.mongoDB.Find(ctx, bson.M{
+		"_id": bson.M{
+			"$in": objIDs,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cur.Close(ctx)
+		if err != nil {
+			m.logger.Err(err)
+			return
+		}
+	}()
+	res := make([]chat_models.Message, 0, len(objIDs))
+	for cur.Next(ctx) {
+		curr := chat_models.BSONMessage{}
+		err = cur.Decode(&curr)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, curr.ToMessage())
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
 func (m *MessageRepoImpl) InsertMessage(ctx context.Context, msg chat_models.Message) (chat_models.Message, error) {
 	res, err := m.mongoDB.InsertOne(ctx, msg)
 	if err != nil {
@@ -64,35 +167,187 @@ func (m *MessageRepoImpl) InsertMessage(ctx context.Context, msg chat_models.Mes
 	return msg, nil
 }
 
+// UpdateMessage overwrites content of the message, tombstones are never updated
 func (m *MessageRepoImpl) UpdateMessage(ctx context.Context, msg chat_models.Message) error {
 	objID, err := bson.ObjectIDFromHex(msg.MessageID)
 	if err != nil {
 		return err
 	}
-	_, err = m.mongoDB.UpdateByID(ctx, objID, m.getUpdateDocumentFromMsg(msg))
+	res, err := m.mongoDB.UpdateOne(ctx, bson.M{
+		"_id": objID,
+		"deleted_at": bson.M{
+			"$exists": false,
+		},
+	}, m.getUpdateDocumentFromMsg(msg))
 	if err != nil {
 		return err
 	}
+	if res.MatchedCount == 0 {
+		return custom_errors.ErrNotFound
+	}
 
 	return nil
 }
 
-func (m *MessageRepoImpl) DeleteMessage(ctx context.Context, msg chat_models.Message) error {
+// EditMessage replaces content of the message and keeps the previous one in revisions
+func (m *MessageRepoImpl) EditMessage(ctx context.Context, msg chat_models.Message, revision chat_models.MessageRevision) error {
 	objID, err := bson.ObjectIDFromHex(msg.MessageID)
 	if err != nil {
 		return err
 	}
-	filter := bson.D{
-		{"_id", objID},
+	update := m.getUpdateDocumentFromMsg(msg)
+	update["$set"].(bson.M)["edited_at"] = msg.EditedAt
+	update["$push"] = bson.M{
+		"revisions": bson.M{
+			"$each":  bson.A{revision},
+			"$slice": -chat_models.MaxMessageRevisions,
+		},
 	}
-	_, err = m.mongoDB.DeleteOne(ctx, filter)
+	res, err := m.mongoDB.UpdateOne(ctx, bson.M{
+		"_id": objID,
+		"deleted_at": bson.M{
+			"$exists": false,
+		},
+	}, update)
 	if err != nil {
 		return err
 	}
+	if res.MatchedCount == 0 {
+		return custom_errors.ErrNotFound
+	}
 
 	return nil
 }
 
+// DeleteMessage turns the message into a tombstone: content, reactions and revisions are cleared
+func (m *MessageRepoImpl) DeleteMessage(ctx context.Context, tombstone chat_models.Message) error {
+	objID, err := bson.ObjectIDFromHex(tombstone.MessageID)
+	if err != nil {
+		return err
+	}
+	_, err = m.mongoDB.UpdateByID(ctx, objID, bson.M{
+		"$set": bson.M{
+			"payload":          "",
+			"structurized":     "",
+			"attachments":      []chat_models.Attachment{},
+			"voice":            "",
+			"voice_duration":   0,
+			"recognized_voice": "",
+			"updated_at":       tombstone.UpdatedAt,
+			"deleted_at":       tombstone.DeletedAt,
+		},
+		"$unset": bson.M{
+			"reactions":             "",
+			"revisions":             "",
+			"structurized_versions": "",
+			"study_material_id":     "",
+			"forwarded_from":        "",
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AddReaction adds user to the reaction, $addToSet keeps only one reaction of the kind per user
+func (m *MessageRepoImpl) AddReaction(ctx context.Context, messageID, reaction, userID string, updatedAt int64) (*chat_models.Message, error) {
+	return m.findAndUpdateMessage(ctx, messageID, bson.M{
+		"$addToSet": bson.M{
+			"reactions." + reaction: userID,
+		},
+		"$set": bson.M{
+			"updated_at": updatedAt,
+		},
+	})
+}
+
+// RemoveReaction removes user from the reaction, the reaction is dropped when nobody is left
+func (m *MessageRepoImpl) RemoveReaction(ctx context.Context, messageID, reaction, userID string, updatedAt int64) (*chat_models.Message, error) {
+	msg, err := m.findAndUpdateMessage(ctx, messageID, bson.M{
+		"$pull": bson.M{
+			"reactions." + reaction: userID,
+		},
+		"$set": bson.M{
+			"updated_at": updatedAt,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(msg.Reactions[reaction]) > 0 {
+		return msg, nil
+	}
+	objID, err := bson.ObjectIDFromHex(messageID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = m.mongoDB.UpdateOne(ctx, bson.M{
+		"_id":                   objID,
+		"reactions." + reaction: bson.M{"$size": 0},
+	}, bson.M{
+		"$unset": bson.M{
+			"reactions." + reaction: "",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	delete(msg.Reactions, reaction)
+
+	return msg, nil
+}
+
+func (m *MessageRepoImpl) findAndUpdateMessage(ctx context.Context, messageID string, update bson.M) (*chat_models.Message, error) {
+	objID, err := bson.ObjectIDFromHex(messageID)
+	if err != nil {
+		return nil, err
+	}
+	res := m.mongoDB.FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"_id": objID,
+			"deleted_at": bson.M{
+				"$exists": false,
+			},
+		},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+	bsonMsg := &chat_models.BSONMessage{}
+	err = res.Decode(bsonMsg)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, custom_errors.ErrNotFound
+		}
+		return nil, err
+	}
+	msg := bsonMsg.ToMessage()
+
+	return &msg, nil
+}
+
+// SetStructurized appends versions and makes the last one current.
+// Only structurization fields are updated, so edits made while the explanation was generated are kept
+func (m *MessageRepoImpl) SetStructurized(ctx context.Context, messageID string, versions []chat_models.StructurizedVersion, updatedAt int64) (*chat_models.Message, error) {
+	if len(versions) == 0 {
+		return nil, custom_errors.ErrBadRequest
+	}
+	return m.findAndUpdateMessage(ctx, messageID, bson.M{
+		"$set": bson.M{
+			"structurized": versions[len(versions)-1].Structurized,
+			"updated_at":   updatedAt,
+		},
+		"$push": bson.M{
+			"structurized_versions": bson.M{
+				"$each":  versions,
+				"$slice": -maxStructurizedVersions,
+			},
+		},
+	})
+}
+
 func (m *MessageRepoImpl) getUpdateDocumentFromMsg(msg chat_models.Message) bson.M {
 	return bson.M{
 		"$set": bson.M{
@@ -107,17 +362,143 @@ func (m *MessageRepoImpl) getUpdateDocumentFromMsg(msg chat_models.Message) bson
 	}
 }
 
-func (m *MessageRepoImpl) GetMessagesByChannelID(ctx context.Context, channelID string, limit, offset int64) ([]chat_models.Message, error) {
+// GetMessagesByChannelID returns messages sorted from the newest to the oldest regardless of cursor direction
+func (m *MessageRepoImpl) GetMessagesByChannelID(ctx context.Context, channelID string, query chat_models.MessagesQuery) ([]chat_models.Message, error) {
+	filter := bson.M{
+		"channel_id": channelID,
+	}
+	sortOrder := -1
+	cursor, cmp := query.Before, "$lt"
+	if query.After != nil {
+		cursor, cmp = query.After, "$gt"
+		sortOrder = 1
+	}
+	if cursor != nil {
+		err := m.applyCursorFilter(filter, cursor, cmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	cur, err := m.mongoDB.Find(
+		ctx,
+		filter,
+		options.Find().SetSort(
+			bson.D{
+				{Key: "created_at", Value: sortOrder},
+				{Key: "_id", Value: sortOrder},
+			},
+		).SetLimit(query.Limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cur.Close(ctx)
+		if err != nil {
+			m.logger.Err(err)
+			return
+		}
+	}()
+	res := make([]chat_models.Message, 0, cur.RemainingBatchLength())
+	for cur.Next(ctx) {
+		curr := chat_models.BSONMessage{}
+		err = cur.Decode(&curr)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, curr.ToMessage())
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	if sortOrder == 1 {
+		slices.Reverse(res)
+	}
+
+	return res, nil
+}
+
+// SearchMessages finds messages by the text index in the given channels, newest first.
+// Only before cursor is supported
+func (m *MessageRepoImpl) SearchMessages(ctx context.Context, channelIDs []string, text string, query chat_models.MessagesQuery) ([]chat_models.Message, error) {
+	if len(channelIDs) == 0 {
+		return []chat_models.Message{}, nil
+	}
+	filter := bson.M{
+		"$text": bson.M{
+			"$search": text,
+		},
+		"channel_id": bson.M{
+			"$in": channelIDs,
+		},
+		"deleted_at": bson.M{
+			"$exists": false,
+		},
+	}
+	if query.Before != nil {
+		err := m.applyCursorFilter(filter, query.Before, "$lt")
+		if err != nil {
+			return nil, err
+		}
+	}
+	cur, err := m.mongoDB.Find(
+		ctx,
+		filter,
+		options.Find().SetSort(
+			bson.D{
+				{Key: "created_at", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+		).SetLimit(query.Limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cur.Close(ctx)
+		if err != nil {
+			m.logger.Err(err)
+			return
+		}
+	}()
+	res := make([]chat_models.Message, 0, cur.RemainingBatchLength())
+	for cur.Next(ctx) {
+		curr := chat_models.BSONMessage{}
+		err = cur.Decode(&curr)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, curr.ToMessage())
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetMessagesChangedSince returns messages created or updated at since or later, the oldest change first
+func (m *MessageRepoImpl) GetMessagesChangedSince(ctx context.Context, channelIDs []string, since, limit int64) ([]chat_models.Message, error) {
+	if len(channelIDs) == 0 {
+		return []chat_models.Message{}, nil
+	}
 	cur, err := m.mongoDB.Find(
 		ctx,
 		bson.M{
-			"channel_id": channelID,
+			"channel_id": bson.M{
+				"$in": channelIDs,
+			},
+			"updated_at": bson.M{
+				"$gte": since,
+			},
 		},
 		options.Find().SetSort(
-			bson.M{
-				"created_at": -1,
+			bson.D{
+				{Key: "updated_at", Value: 1},
+				{Key: "_id", Value: 1},
 			},
-		).SetLimit(limit).SetSkip(offset),
+		).SetLimit(limit),
 	)
 	if err != nil {
 		return nil, err
@@ -153,6 +534,9 @@ func (m *MessageRepoImpl) GetPreviousMessagesByMessageCreatedAt(ctx context.Cont
 			"created_at": bson.M{
 				"$lt": createdAt,
 			},
+			"deleted_at": bson.M{
+				"$exists": false,
+			},
 		},
 		options.Find().SetSort(
 			bson.M{
@@ -185,3 +569,130 @@ func (m *MessageRepoImpl) GetPreviousMessagesByMessageCreatedAt(ctx context.Cont
 
 	return res, nil
 }
+
+// GetMessagesInWindow returns the latest limit messages created in [from, to] in chronological order
+func (m *MessageRepoImpl) GetMessagesInWindow(ctx context.Context, channelID string, from, to, limit int64) ([]chat_models.Message, error) {
+	cur, err := m.mongoDB.Find(
+		ctx,
+		bson.M{
+			"channel_id": channelID,
+			"created_at": bson.M{
+				"$gte": from,
+				"$lte": to,
+			},
+			"deleted_at": bson.M{
+				"$exists": false,
+			},
+		},
+		options.Find().SetSort(
+			bson.D{
+				{Key: "created_at", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+		).SetLimit(limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cur.Close(ctx)
+		if err != nil {
+			m.logger.Err(err)
+			return
+		}
+	}()
+	res := make([]chat_models.Message, 0, cur.RemainingBatchLength())
+	for cur.Next(ctx) {
+		curr := chat_models.BSONMessage{}
+		err = cur.Decode(&curr)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, curr.ToMessage())
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	slices.Reverse(res)
+
+	return res, nil
+}
+
+// ForEachChannelMessage calls fn for every not deleted message of the channel from the oldest to the newest.
+// Messages are decoded from the cursor one by one, so the history is never loaded at once
+func (m *MessageRepoImpl) ForEachChannelMessage(ctx context.Context, channelID string, fn func(msg chat_models.Message) error) error {
+	cur, err := m.mongoDB.Find(
+		ctx,
+		bson.M{
+			"channel_id": channelID,
+			"deleted_at": bson.M{
+				"$exists": false,
+			},
+		},
+		options.Find().SetSort(
+			bson.D{
+				{Key: "created_at", Value: 1},
+				{Key: "_id", Value: 1},
+			},
+		),
+	)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = cur.Close(ctx)
+		if err != nil {
+			m.logger.Err(err)
+			return
+		}
+	}()
+	for cur.Next(ctx) {
+		curr := chat_models.BSONMessage{}
+		err = cur.Decode(&curr)
+		if err != nil {
+			return err
+		}
+		if err = fn(curr.ToMessage()); err != nil {
+			return err
+		}
+	}
+	return cur.Err()
+}
+
+// CountUnreadMessages counts messages of other users after lastRead, if lastRead is nil - all of them
+func (m *MessageRepoImpl) CountUnreadMessages(ctx context.Context, channelID, userID string, lastRead *chat_models.MessageCursor) (int64, error) {
+	filter := bson.M{
+		"channel_id": channelID,
+		"user_id": bson.M{
+			"$ne": userID,
+		},
+		"deleted_at": bson.M{
+			"$exists": false,
+		},
+	}
+	if lastRead != nil {
+		err := m.applyCursorFilter(filter, lastRead, "$gt")
+		if err != nil {
+			return 0, err
+		}
+	}
+	return m.mongoDB.CountDocuments(ctx, filter)
+}
+
+// applyCursorFilter adds (created_at, _id) comparison with cursor to filter, cmp is $lt or $gt
+func (m *MessageRepoImpl) applyCursorFilter(filter bson.M, cursor *chat_models.MessageCursor, cmp string) error {
+	cursorID, err := bson.ObjectIDFromHex(cursor.MessageID)
+	if err != nil {
+		return custom_errors.ErrInvalidCursor
+	}
+	filter["$or"] = bson.A{
+		bson.M{
+			"created_at": bson.M{cmp: cursor.CreatedAt},
+		},
+		bson.M{
+			"created_at": cursor.CreatedAt,
+			"_id":        bson.M{cmp: cursorID},
+		},
+	}
+	return nil
+}
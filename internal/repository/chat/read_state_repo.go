@@ -0,0 +1,120 @@
+package message_repo
+
+import (
+	"context"
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type ReadStateRepo interface {
+	EnsureIndexes(ctx context.Context) error
+	UpsertReadState(ctx context.Context, state chat_models.ReadState) (bool, error)
+	GetReadStatesByUserID(ctx context.Context, userID string, channelIDs []string) (map[string]chat_models.ReadState, error)
+}
+
+type ReadStateRepoImpl struct {
+	mongoDB *mongo.Collection
+	logger  zerolog.Logger
+}
+
+func NewReadStateRepo(mongoDB *mongo.Collection, logger zerolog.Logger) ReadStateRepo {
+	return &ReadStateRepoImpl{
+		mongoDB: mongoDB,
+		logger:  logger,
+	}
+}
+
+func (r *ReadStateRepoImpl) EnsureIndexes(ctx context.Context) error {
+	_, err := r.mongoDB.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "user_id", Value: 1},
+			{Key: "channel_id", Value: 1},
+		},
+		Options: options.Index().SetName("user_id_channel_id").SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpsertReadState moves the read marker only forward, the comparison is done by mongo,
+// so concurrent read events (e.g. from two devices) can't move it back.
+// Returns false if the stored marker already points to the same or a later message
+func (r *ReadStateRepoImpl) UpsertReadState(ctx context.Context, state chat_models.ReadState) (bool, error) {
+	_, err := r.mongoDB.UpdateOne(
+		ctx,
+		bson.M{
+			"user_id":    state.UserID,
+			"channel_id": state.ChannelID,
+			"$or": bson.A{
+				bson.M{
+					"last_read_at": bson.M{
+						"$lt": state.LastReadAt,
+					},
+				},
+				bson.M{
+					"last_read_at": state.LastReadAt,
+					"last_read_message_id": bson.M{
+						"$lt": state.LastReadMessageID,
+					},
+				},
+			},
+		},
+		bson.M{
+			"$set": bson.M{
+				"last_read_message_id": state.LastReadMessageID,
+				"last_read_at":         state.LastReadAt,
+				"updated_at":           state.UpdatedAt,
+			},
+		},
+		options.UpdateOne().SetUpsert(true),
+	)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			// the state exists and is not behind, so upsert tried to insert another one
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetReadStatesByUserID returns read states keyed by channel id, channels never read by user are absent
+func (r *ReadStateRepoImpl) GetReadStatesByUserID(ctx context.Context, userID string, channelIDs []string) (map[string]chat_models.ReadState, error) {
+	cur, err := r.mongoDB.Find(ctx, bson.M{
+		"user_id": userID,
+		"channel_id": bson.M{
+			"$in": channelIDs,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cur.Close(ctx)
+		if err != nil {
+			r.logger.Err(err)
+			return
+		}
+	}()
+	res := make(map[string]chat_models.ReadState, len(channelIDs))
+	for cur.Next(ctx) {
+		curr := chat_models.ReadState{}
+		err = cur.Decode(&curr)
+		if err != nil {
+			return nil, err
+		}
+		res[curr.ChannelID] = curr
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
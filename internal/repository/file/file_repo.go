@@ -2,14 +2,22 @@ package filerepo
 
 import (
 	"context"
+	filemodels "github.com/Petr09Mitin/xrust-beze-back/internal/models/file"
 	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/config"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/lifecycle"
 	"github.com/rs/zerolog"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/url"
+	"strings"
 )
 
 type FileRepo interface {
-	UploadTemp(ctx context.Context, filepath, filename string) error
+	UploadTemp(ctx context.Context, filepath, filename, originalName string) error
+	GetTempMetadata(ctx context.Context, filename string) (filemodels.AttachmentMetadata, error)
 	CopyFromTempToAvatars(ctx context.Context, filename string) (err error)
 	CopyFromTempToVoiceMessages(ctx context.Context, filename string) (err error)
 	DeleteAvatar(ctx context.Context, filename string) (err error)
@@ -22,8 +30,12 @@ type FileRepo interface {
 	DeleteAttachments(ctx context.Context, filenames []string) (err error)
 	CheckIfAttachmentExists(ctx context.Context, filename string) (exist bool, err error)
 	CopyFromAttachmentsToStudyMaterials(ctx context.Context, filename string) (err error)
+	CopyAttachment(ctx context.Context, srcFilename, dstFilename string) (err error)
 }
 
+// originalNameMetaKey keeps the name of the uploaded file, escaped since metadata must be ASCII
+const originalNameMetaKey = "Original-Name"
+
 type FileRepoImpl struct {
 	minioClient *minio.Client
 	logger      zerolog.Logger
@@ -57,14 +69,52 @@ func NewFileRepo(minioClient *minio.Client, logger zerolog.Logger) (FileRepo, er
 	return fr, nil
 }
 
-func (f *FileRepoImpl) UploadTemp(ctx context.Context, filepath, filename string) error {
-	_, err := f.minioClient.FPutObject(ctx, config.TempMinioBucket, filename, filepath, minio.PutObjectOptions{})
+func (f *FileRepoImpl) UploadTemp(ctx context.Context, filepath, filename, originalName string) error {
+	_, err := f.minioClient.FPutObject(ctx, config.TempMinioBucket, filename, filepath, minio.PutObjectOptions{
+		UserMetadata: map[string]string{
+			originalNameMetaKey: url.QueryEscape(originalName),
+		},
+	})
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// GetTempMetadata returns size, content type and original name of the temp file, and dimensions if it is an image
+func (f *FileRepoImpl) GetTempMetadata(ctx context.Context, filename string) (filemodels.AttachmentMetadata, error) {
+	info, err := f.minioClient.StatObject(ctx, config.TempMinioBucket, filename, minio.StatObjectOptions{})
+	if err != nil {
+		return filemodels.AttachmentMetadata{}, err
+	}
+	metadata := filemodels.AttachmentMetadata{
+		Filename: filename,
+		Size:     info.Size,
+		MimeType: info.ContentType,
+	}
+	if originalName, err := url.QueryUnescape(info.UserMetadata[originalNameMetaKey]); err == nil {
+		metadata.OriginalName = originalName
+	}
+	if !strings.HasPrefix(info.ContentType, "image/") {
+		return metadata, nil
+	}
+
+	obj, err := f.minioClient.GetObject(ctx, config.TempMinioBucket, filename, minio.GetObjectOptions{})
+	if err != nil {
+		return filemodels.AttachmentMetadata{}, err
+	}
+	defer obj.Close()
+	cfg, _, err := image.DecodeConfig(obj)
+	if err != nil {
+		// not every image format is supported, dimensions are optional
+		f.logger.Warn().Err(err).Str("filename", filename).Msg("unable to decode image config")
+		return metadata, nil
+	}
+	metadata.Width = cfg.Width
+	metadata.Height = cfg.Height
+	return metadata, nil
+}
+
 func (f *FileRepoImpl) CopyFromTempToAvatars(ctx context.Context, filename string) error {
 	_, err := f.minioClient.CopyObject(ctx, minio.CopyDestOptions{
 		Bucket: config.AvatarsMinioBucket,
@@ -310,3 +360,17 @@ func (f *FileRepoImpl) CopyFromAttachmentsToStudyMaterials(ctx context.Context,
 	}
 	return nil
 }
+
+func (f *FileRepoImpl) CopyAttachment(ctx context.Context, srcFilename, dstFilename string) (err error) {
+	_, err = f.minioClient.CopyObject(ctx, minio.CopyDestOptions{
+		Bucket: config.AttachmentsMinioBucket,
+		Object: dstFilename,
+	}, minio.CopySrcOptions{
+		Bucket: config.AttachmentsMinioBucket,
+		Object: srcFilename,
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package file_client
 
 import (
 	"context"
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
 	filepb "github.com/Petr09Mitin/xrust-beze-back/proto/file"
 	"github.com/rs/zerolog"
 )
@@ -11,8 +12,9 @@ type FileServiceClient interface {
 	DeleteAvatar(ctx context.Context, filename string) error
 	MoveTempFileToVoiceMessages(ctx context.Context, filename string) (string, error)
 	DeleteVoiceMessage(ctx context.Context, filename string) error
-	MoveTempFilesToAttachments(ctx context.Context, filenames []string) ([]string, error)
+	MoveTempFilesToAttachments(ctx context.Context, filenames []string) ([]chat_models.Attachment, error)
 	DeleteAttachments(ctx context.Context, filenames []string) error
+	CopyAttachments(ctx context.Context, filenames []string) ([]string, error)
 }
 
 type FileServiceClientImpl struct {
@@ -71,7 +73,7 @@ func (f *FileServiceClientImpl) DeleteVoiceMessage(ctx context.Context, filename
 	return nil
 }
 
-func (f *FileServiceClientImpl) MoveTempFilesToAttachments(ctx context.Context, filenames []string) ([]string, error) {
+func (f *FileServiceClientImpl) MoveTempFilesToAttachments(ctx context.Context, filenames []string) ([]chat_models.Attachment, error) {
 	res, err := f.fileGRPC.MoveTempFilesToAttachments(ctx, &filepb.MoveTempFilesToAttachmentsRequest{
 		Filenames: filenames,
 	})
@@ -79,7 +81,26 @@ func (f *FileServiceClientImpl) MoveTempFilesToAttachments(ctx context.Context,
 		return nil, err
 	}
 
-	return res.GetFilenames(), nil
+	// file service without metadata support returns only filenames
+	if len(res.GetAttachments()) == 0 {
+		attachments := make([]chat_models.Attachment, 0, len(res.GetFilenames()))
+		for _, filename := range res.GetFilenames() {
+			attachments = append(attachments, chat_models.Attachment{Filename: filename})
+		}
+		return attachments, nil
+	}
+	attachments := make([]chat_models.Attachment, 0, len(res.GetAttachments()))
+	for _, attachment := range res.GetAttachments() {
+		attachments = append(attachments, chat_models.Attachment{
+			Filename:     attachment.GetFilename(),
+			OriginalName: attachment.GetOriginalName(),
+			Size:         attachment.GetSize(),
+			MimeType:     attachment.GetMimeType(),
+			Width:        int(attachment.GetWidth()),
+			Height:       int(attachment.GetHeight()),
+		})
+	}
+	return attachments, nil
 }
 
 func (f *FileServiceClientImpl) DeleteAttachments(ctx context.Context, filenames []string) error {
@@ -92,3 +113,14 @@ func (f *FileServiceClientImpl) DeleteAttachments(ctx context.Context, filenames
 
 	return nil
 }
+
+func (f *FileServiceClientImpl) CopyAttachments(ctx context.Context, filenames []string) ([]string, error) {
+	res, err := f.fileGRPC.CopyAttachments(ctx, &filepb.CopyAttachmentsRequest{
+		Filenames: filenames,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.GetFilenames(), nil
+}
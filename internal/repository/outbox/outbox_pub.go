@@ -0,0 +1,63 @@
+package outbox_repo
+
+import (
+	"time"
+
+	outbox_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/outbox"
+	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/mongotx"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/rs/zerolog"
+)
+
+type Notifier interface {
+	Notify()
+}
+
+// OutboxPub is a watermill publisher that saves messages to the outbox instead of the broker.
+// Message context is used for the insert, so publishing inside mongo transaction is atomic with it
+type OutboxPub struct {
+	repo     OutboxRepo
+	notifier Notifier
+	logger   zerolog.Logger
+}
+
+func NewOutboxPub(repo OutboxRepo, notifier Notifier, logger zerolog.Logger) message.Publisher {
+	return &OutboxPub{
+		repo:     repo,
+		notifier: notifier,
+		logger:   logger,
+	}
+}
+
+func (o *OutboxPub) Publish(topic string, messages ...*message.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	now := time.Now()
+	entries := make([]outbox_models.Entry, 0, len(messages))
+	for _, msg := range messages {
+		entries = append(entries, outbox_models.Entry{
+			UUID:          msg.UUID,
+			Topic:         topic,
+			Key:           msg.Metadata.Get(outbox_models.KeyMetadata),
+			Payload:       msg.Payload,
+			Metadata:      msg.Metadata,
+			Status:        outbox_models.PendingEntryStatus,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+	ctx := messages[0].Context()
+	err := o.repo.InsertEntries(ctx, entries)
+	if err != nil {
+		return err
+	}
+	// inside a transaction the entries are visible to the relay only after the commit
+	mongotx.AfterCommit(ctx, o.notifier.Notify)
+
+	return nil
+}
+
+func (o *OutboxPub) Close() error {
+	return nil
+}
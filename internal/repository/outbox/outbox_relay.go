@@ -0,0 +1,128 @@
+package outbox_repo
+
+import (
+	"context"
+	"time"
+
+	outbox_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/outbox"
+	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/backoff"
+	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/config"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/rs/zerolog"
+)
+
+const (
+	defaultPollInterval = 500 * time.Millisecond
+	defaultBatchSize    = 100
+	defaultMaxAttempts  = 20
+	claimLease          = 30 * time.Second
+	minRetryBackoff     = time.Second
+	maxRetryBackoff     = 5 * time.Minute
+)
+
+// OutboxRelay publishes pending outbox entries to the broker, several relays can work on the same collection
+type OutboxRelay struct {
+	repo         OutboxRepo
+	pub          message.Publisher
+	pollInterval time.Duration
+	batchSize    int
+	maxAttempts  int
+	notify       chan struct{}
+	logger       zerolog.Logger
+}
+
+func NewOutboxRelay(repo OutboxRepo, pub message.Publisher, cfg *config.Outbox, logger zerolog.Logger) *OutboxRelay {
+	r := &OutboxRelay{
+		repo:         repo,
+		pub:          pub,
+		pollInterval: defaultPollInterval,
+		batchSize:    defaultBatchSize,
+		maxAttempts:  defaultMaxAttempts,
+		notify:       make(chan struct{}, 1),
+		logger:       logger,
+	}
+	if cfg != nil {
+		if cfg.PollIntervalMs > 0 {
+			r.pollInterval = time.Duration(cfg.PollIntervalMs) * time.Millisecond
+		}
+		if cfg.BatchSize > 0 {
+			r.batchSize = cfg.BatchSize
+		}
+		if cfg.MaxAttempts > 0 {
+			r.maxAttempts = cfg.MaxAttempts
+		}
+	}
+	return r
+}
+
+// Notify wakes the relay up without waiting for the poll interval
+func (r *OutboxRelay) Notify() {
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Run publishes entries until ctx is canceled
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+	for {
+		r.publishPending(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.notify:
+		}
+	}
+}
+
+func (r *OutboxRelay) publishPending(ctx context.Context) {
+	for i := 0; i < r.batchSize; i++ {
+		if ctx.Err() != nil {
+			return
+		}
+		entry, err := r.repo.ClaimEntry(ctx, time.Now(), claimLease)
+		if err != nil {
+			r.logger.Error().Err(err).Msg("unable to claim outbox entry")
+			return
+		}
+		if entry == nil {
+			return
+		}
+		r.publishEntry(ctx, *entry)
+	}
+}
+
+func (r *OutboxRelay) publishEntry(ctx context.Context, entry outbox_models.Entry) {
+	msg := message.NewMessage(entry.UUID, entry.Payload)
+	for key, value := range entry.Metadata {
+		msg.Metadata.Set(key, value)
+	}
+	err := r.pub.Publish(entry.Topic, msg)
+	if err == nil {
+		err = r.repo.MarkSent(ctx, entry.ID, time.Now())
+		if err != nil {
+			// entry will be published again after the lease
+			r.logger.Error().Err(err).Str("uuid", entry.UUID).Msg("unable to mark outbox entry as sent")
+		}
+		return
+	}
+
+	entry.Attempts++
+	entry.LastError = err.Error()
+	entry.NextAttemptAt = time.Now().Add(backoff.Exponential(entry.Attempts, minRetryBackoff, maxRetryBackoff))
+	if entry.Attempts >= r.maxAttempts {
+		entry.Status = outbox_models.FailedEntryStatus
+	}
+	r.logger.Error().Err(err).
+		Str("uuid", entry.UUID).
+		Str("topic", entry.Topic).
+		Int("attempts", entry.Attempts).
+		Msg("unable to publish outbox entry")
+	err = r.repo.MarkFailed(ctx, entry)
+	if err != nil {
+		r.logger.Error().Err(err).Str("uuid", entry.UUID).Msg("unable to mark outbox entry as failed")
+	}
+}
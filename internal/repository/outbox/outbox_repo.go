@@ -0,0 +1,239 @@
+package outbox_repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	outbox_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/outbox"
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	// sent entries are kept for debugging and removed by mongo afterwards
+	sentEntriesTTL = 7 * 24 * time.Hour
+	// due entries looked through on claim, the earlier ones of their keys may be still pending
+	claimCandidates = 100
+)
+
+type OutboxRepo interface {
+	EnsureIndexes(ctx context.Context) error
+	InsertEntries(ctx context.Context, entries []outbox_models.Entry) error
+	ClaimEntry(ctx context.Context, now time.Time, lease time.Duration) (*outbox_models.Entry, error)
+	MarkSent(ctx context.Context, id bson.ObjectID, sentAt time.Time) error
+	MarkFailed(ctx context.Context, entry outbox_models.Entry) error
+}
+
+type OutboxRepoImpl struct {
+	mongoDB *mongo.Collection
+	logger  zerolog.Logger
+}
+
+func NewOutboxRepo(mongoDB *mongo.Collection, logger zerolog.Logger) OutboxRepo {
+	return &OutboxRepoImpl{
+		mongoDB: mongoDB,
+		logger:  logger,
+	}
+}
+
+func (o *OutboxRepoImpl) EnsureIndexes(ctx context.Context) error {
+	_, err := o.mongoDB.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "next_attempt_at", Value: 1},
+				{Key: "created_at", Value: 1},
+			},
+			Options: options.Index().SetName("status_next_attempt_at_created_at"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "key", Value: 1},
+				{Key: "status", Value: 1},
+				{Key: "created_at", Value: 1},
+			},
+			Options: options.Index().SetName("key_status_created_at"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "sent_at", Value: 1},
+			},
+			Options: options.Index().
+				SetName("sent_at_ttl").
+				SetExpireAfterSeconds(int32(sentEntriesTTL.Seconds())),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// InsertEntries saves entries, with a transaction context they are saved atomically with other changes
+func (o *OutboxRepoImpl) InsertEntries(ctx context.Context, entries []outbox_models.Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	_, err := o.mongoDB.InsertMany(ctx, entries)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ClaimEntry takes the oldest pending entry that is due and hides it from other relays for lease.
+// An entry with a key is taken only when no earlier entry with the key is pending, so a failing entry
+// holds back the later events of its key (e.g. channel) until it is sent or given up.
+// If the relay dies before marking the entry, it is published again after the lease (at least once delivery).
+// Returns nil if there is nothing to publish
+func (o *OutboxRepoImpl) ClaimEntry(ctx context.Context, now time.Time, lease time.Duration) (*outbox_models.Entry, error) {
+	// keys with an entry in backoff or claimed by a relay are not published at all
+	var blockedKeys []string
+	err := o.mongoDB.Distinct(ctx, "key", bson.M{
+		"status": outbox_models.PendingEntryStatus,
+		"next_attempt_at": bson.M{
+			"$gt": now,
+		},
+		"key": bson.M{
+			"$exists": true,
+		},
+	}).Decode(&blockedKeys)
+	if err != nil {
+		return nil, err
+	}
+	filter := bson.M{
+		"status": outbox_models.PendingEntryStatus,
+		"next_attempt_at": bson.M{
+			"$lte": now,
+		},
+	}
+	if len(blockedKeys) > 0 {
+		filter["key"] = bson.M{
+			"$nin": blockedKeys,
+		}
+	}
+	cur, err := o.mongoDB.Find(
+		ctx,
+		filter,
+		options.Find().
+			SetSort(bson.D{
+				{Key: "next_attempt_at", Value: 1},
+				{Key: "created_at", Value: 1},
+				{Key: "_id", Value: 1},
+			}).
+			SetLimit(claimCandidates),
+	)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]outbox_models.Entry, 0, claimCandidates)
+	err = cur.All(ctx, &candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, candidate := range candidates {
+		if candidate.Key != "" {
+			blocked, err := o.hasEarlierPending(ctx, candidate)
+			if err != nil {
+				return nil, err
+			}
+			if blocked {
+				continue
+			}
+		}
+		res := o.mongoDB.FindOneAndUpdate(
+			ctx,
+			bson.M{
+				"_id":    candidate.ID,
+				"status": outbox_models.PendingEntryStatus,
+				"next_attempt_at": bson.M{
+					"$lte": now,
+				},
+			},
+			bson.M{
+				"$set": bson.M{
+					"next_attempt_at": now.Add(lease),
+				},
+			},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		)
+		entry := &outbox_models.Entry{}
+		err = res.Decode(entry)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				// claimed by another relay
+				continue
+			}
+			return nil, err
+		}
+		return entry, nil
+	}
+
+	return nil, nil
+}
+
+// hasEarlierPending reports whether an entry with the same key saved before entry is not published yet
+func (o *OutboxRepoImpl) hasEarlierPending(ctx context.Context, entry outbox_models.Entry) (bool, error) {
+	err := o.mongoDB.FindOne(ctx, bson.M{
+		"key":    entry.Key,
+		"status": outbox_models.PendingEntryStatus,
+		"$or": bson.A{
+			bson.M{
+				"created_at": bson.M{
+					"$lt": entry.CreatedAt,
+				},
+			},
+			bson.M{
+				"created_at": entry.CreatedAt,
+				"_id": bson.M{
+					"$lt": entry.ID,
+				},
+			},
+		},
+	}, options.FindOne().SetProjection(bson.M{"_id": 1})).Err()
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (o *OutboxRepoImpl) MarkSent(ctx context.Context, id bson.ObjectID, sentAt time.Time) error {
+	_, err := o.mongoDB.UpdateByID(ctx, id, bson.M{
+		"$set": bson.M{
+			"status":  outbox_models.SentEntryStatus,
+			"sent_at": sentAt,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarkFailed saves attempt result: status, attempts, next attempt time and error
+func (o *OutboxRepoImpl) MarkFailed(ctx context.Context, entry outbox_models.Entry) error {
+	_, err := o.mongoDB.UpdateByID(ctx, entry.ID, bson.M{
+		"$set": bson.M{
+			"status":          entry.Status,
+			"attempts":        entry.Attempts,
+			"next_attempt_at": entry.NextAttemptAt,
+			"last_error":      entry.LastError,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package presence_repo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+)
+
+const (
+	presenceKeyPrefix = "xb:chat:presence:"
+)
+
+// PresenceRepo tracks websocket connections of users across all chat replicas.
+// Every user has a sorted set of connection ids scored by expiration time,
+// so connections of a crashed replica expire on their own
+type PresenceRepo interface {
+	AddConnection(ctx context.Context, userID, connID string) (becameOnline bool, err error)
+	RefreshConnection(ctx context.Context, userID, connID string) error
+	RemoveConnection(ctx context.Context, userID, connID string) (becameOffline bool, err error)
+	GetOnlineUserIDs(ctx context.Context, userIDs []string) ([]string, error)
+}
+
+type PresenceRepoImpl struct {
+	client *redis.Client
+	ttl    time.Duration
+	logger zerolog.Logger
+}
+
+func NewPresenceRepo(client *redis.Client, ttl time.Duration, logger zerolog.Logger) PresenceRepo {
+	return &PresenceRepoImpl{
+		client: client,
+		ttl:    ttl,
+		logger: logger,
+	}
+}
+
+func (r *PresenceRepoImpl) AddConnection(ctx context.Context, userID, connID string) (bool, error) {
+	key := r.key(userID)
+	now := time.Now()
+	pipe := r.client.TxPipeline()
+	r.removeExpired(ctx, pipe, key, now)
+	countBefore := pipe.ZCard(ctx, key)
+	r.addOrRefresh(ctx, pipe, key, connID, now)
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return countBefore.Val() == 0, nil
+}
+
+func (r *PresenceRepoImpl) RefreshConnection(ctx context.Context, userID, connID string) error {
+	pipe := r.client.TxPipeline()
+	r.addOrRefresh(ctx, pipe, r.key(userID), connID, time.Now())
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *PresenceRepoImpl) RemoveConnection(ctx context.Context, userID, connID string) (bool, error) {
+	key := r.key(userID)
+	pipe := r.client.TxPipeline()
+	pipe.ZRem(ctx, key, connID)
+	r.removeExpired(ctx, pipe, key, time.Now())
+	countAfter := pipe.ZCard(ctx, key)
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return countAfter.Val() == 0, nil
+}
+
+func (r *PresenceRepoImpl) GetOnlineUserIDs(ctx context.Context, userIDs []string) ([]string, error) {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	pipe := r.client.Pipeline()
+	counts := make([]*redis.IntCmd, 0, len(userIDs))
+	for _, userID := range userIDs {
+		counts = append(counts, pipe.ZCount(ctx, r.key(userID), "("+now, "+inf"))
+	}
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]string, 0, len(userIDs))
+	for i, count := range counts {
+		if count.Val() > 0 {
+			res = append(res, userIDs[i])
+		}
+	}
+	return res, nil
+}
+
+func (r *PresenceRepoImpl) addOrRefresh(ctx context.Context, pipe redis.Pipeliner, key, connID string, now time.Time) {
+	pipe.ZAdd(ctx, key, redis.Z{
+		Score:  float64(now.Add(r.ttl).UnixMilli()),
+		Member: connID,
+	})
+	pipe.Expire(ctx, key, r.ttl)
+}
+
+func (r *PresenceRepoImpl) removeExpired(ctx context.Context, pipe redis.Pipeliner, key string, now time.Time) {
+	pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.UnixMilli(), 10))
+}
+
+func (r *PresenceRepoImpl) key(userID string) string {
+	return fmt.Sprintf("%s%s", presenceKeyPrefix, userID)
+}
@@ -0,0 +1,70 @@
+package ratelimit_repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+)
+
+const (
+	rateLimitKeyPrefix = "xb:chat:ratelimit:"
+)
+
+// tokenBucketScript takes a token from the bucket stored in a hash.
+// Redis time is used, so all replicas share the same clock.
+// Returns {allowed, retry after in ms}
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil then
+	tokens = burst
+	ts = now
+end
+tokens = math.min(burst, tokens + (now - ts) * rate / 1000)
+
+local allowed = 0
+local retry_after = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry_after = math.ceil((1 - tokens) * 1000 / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst * 1000 / rate) + 1000)
+return {allowed, retry_after}
+`)
+
+// RateLimitRepo is a token bucket limiter shared by all chat replicas
+type RateLimitRepo interface {
+	Allow(ctx context.Context, key string, rate float64, burst int) (allowed bool, retryAfter time.Duration, err error)
+}
+
+type RateLimitRepoImpl struct {
+	client *redis.Client
+	logger zerolog.Logger
+}
+
+func NewRateLimitRepo(client *redis.Client, logger zerolog.Logger) RateLimitRepo {
+	return &RateLimitRepoImpl{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (r *RateLimitRepoImpl) Allow(ctx context.Context, key string, rate float64, burst int) (bool, time.Duration, error) {
+	res, err := tokenBucketScript.Run(ctx, r.client, []string{rateLimitKeyPrefix + key}, rate, burst).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	return res[0] == 1, time.Duration(res[1]) * time.Millisecond, nil
+}
@@ -0,0 +1,132 @@
+package ratelimit_repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+)
+
+func TestAllowTokenBucket(t *testing.T) {
+	type step struct {
+		// elapsed since the previous step
+		elapsed        time.Duration
+		key            string
+		wantAllowed    bool
+		wantRetryAfter time.Duration
+	}
+	tests := []struct {
+		name  string
+		rate  float64
+		burst int
+		steps []step
+	}{
+		{
+			name:  "burst then retry after one token",
+			rate:  1,
+			burst: 3,
+			steps: []step{
+				{wantAllowed: true},
+				{wantAllowed: true},
+				{wantAllowed: true},
+				{wantRetryAfter: time.Second},
+			},
+		},
+		{
+			name:  "retry after is shorter for higher rate",
+			rate:  4,
+			burst: 1,
+			steps: []step{
+				{wantAllowed: true},
+				{wantRetryAfter: 250 * time.Millisecond},
+			},
+		},
+		{
+			name:  "partially refilled token",
+			rate:  1,
+			burst: 1,
+			steps: []step{
+				{wantAllowed: true},
+				{elapsed: 400 * time.Millisecond, wantRetryAfter: 600 * time.Millisecond},
+				{elapsed: 600 * time.Millisecond, wantAllowed: true},
+			},
+		},
+		{
+			name:  "denied request takes no token",
+			rate:  2,
+			burst: 1,
+			steps: []step{
+				{wantAllowed: true},
+				{wantRetryAfter: 500 * time.Millisecond},
+				{wantRetryAfter: 500 * time.Millisecond},
+				{elapsed: 500 * time.Millisecond, wantAllowed: true},
+			},
+		},
+		{
+			name:  "refill is capped by burst",
+			rate:  10,
+			burst: 2,
+			steps: []step{
+				{elapsed: time.Hour, wantAllowed: true},
+				{wantAllowed: true},
+				{wantRetryAfter: 100 * time.Millisecond},
+			},
+		},
+		{
+			name:  "buckets of different keys are independent",
+			rate:  1,
+			burst: 1,
+			steps: []step{
+				{key: "alice:text", wantAllowed: true},
+				{key: "alice:text", wantRetryAfter: time.Second},
+				{key: "bob:text", wantAllowed: true},
+				{key: "alice:voice", wantAllowed: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mr := miniredis.RunT(t)
+			now := time.Unix(1700000000, 0)
+			mr.SetTime(now)
+			client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+			defer client.Close()
+			repo := NewRateLimitRepo(client, zerolog.Nop())
+
+			for i, s := range tt.steps {
+				now = now.Add(s.elapsed)
+				mr.SetTime(now)
+				key := s.key
+				if key == "" {
+					key = "alice:text"
+				}
+				allowed, retryAfter, err := repo.Allow(context.Background(), key, tt.rate, tt.burst)
+				if err != nil {
+					t.Fatalf("step %d: allow: %v", i, err)
+				}
+				if allowed != s.wantAllowed || retryAfter != s.wantRetryAfter {
+					t.Fatalf("step %d: got (%t, %v), want (%t, %v)", i, allowed, retryAfter, s.wantAllowed, s.wantRetryAfter)
+				}
+			}
+		})
+	}
+}
+
+func TestAllowExpiresIdleBucket(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	repo := NewRateLimitRepo(client, zerolog.Nop())
+
+	if _, _, err := repo.Allow(context.Background(), "alice:text", 2, 10); err != nil {
+		t.Fatalf("allow: %v", err)
+	}
+	// the bucket is full again after burst/rate seconds, so it is not kept longer
+	ttl := mr.TTL(rateLimitKeyPrefix + "alice:text")
+	if want := 6 * time.Second; ttl != want {
+		t.Fatalf("got ttl %v, want %v", ttl, want)
+	}
+}
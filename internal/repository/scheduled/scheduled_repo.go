@@ -0,0 +1,237 @@
+package scheduled_repo
+
+import (
+	"context"
+	"errors"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type ScheduledMessageRepo interface {
+	EnsureIndexes(ctx context.Context) error
+	InsertScheduledMessage(ctx context.Context, sm chat_models.ScheduledMessage) (chat_models.ScheduledMessage, error)
+	GetScheduledMessageByID(ctx context.Context, id string) (*chat_models.ScheduledMessage, error)
+	GetPendingByUserID(ctx context.Context, userID, channelID string, limit int64) ([]chat_models.ScheduledMessage, error)
+	Cancel(ctx context.Context, id string, updatedAt int64) error
+	ClaimDue(ctx context.Context, now, lease int64) (*chat_models.ScheduledMessage, error)
+	MarkSent(ctx context.Context, id bson.ObjectID, messageID string, sentAt int64) error
+	MarkFailed(ctx context.Context, sm chat_models.ScheduledMessage) error
+}
+
+type ScheduledMessageRepoImpl struct {
+	mongoDB *mongo.Collection
+	logger  zerolog.Logger
+}
+
+func NewScheduledMessageRepo(mongoDB *mongo.Collection, logger zerolog.Logger) ScheduledMessageRepo {
+	return &ScheduledMessageRepoImpl{
+		mongoDB: mongoDB,
+		logger:  logger,
+	}
+}
+
+func (r *ScheduledMessageRepoImpl) EnsureIndexes(ctx context.Context) error {
+	_, err := r.mongoDB.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "next_attempt_at", Value: 1},
+			},
+			Options: options.Index().SetName("status_next_attempt_at"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+				{Key: "status", Value: 1},
+				{Key: "send_at", Value: 1},
+			},
+			Options: options.Index().SetName("user_id_status_send_at"),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *ScheduledMessageRepoImpl) InsertScheduledMessage(ctx context.Context, sm chat_models.ScheduledMessage) (chat_models.ScheduledMessage, error) {
+	res, err := r.mongoDB.InsertOne(ctx, sm)
+	if err != nil {
+		return chat_models.ScheduledMessage{}, err
+	}
+	objID, ok := res.InsertedID.(bson.ObjectID)
+	if !ok {
+		return chat_models.ScheduledMessage{}, custom_errors.ErrInvalidIDType
+	}
+	sm.ID = objID
+
+	return sm, nil
+}
+
+func (r *ScheduledMessageRepoImpl) GetScheduledMessageByID(ctx context.Context, id string) (*chat_models.ScheduledMessage, error) {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, custom_errors.ErrInvalidIDType
+	}
+	sm := &chat_models.ScheduledMessage{}
+	err = r.mongoDB.FindOne(ctx, bson.M{"_id": objID}).Decode(sm)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, custom_errors.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return sm, nil
+}
+
+// GetPendingByUserID returns pending messages of the user ordered by send time, channelID is optional
+func (r *ScheduledMessageRepoImpl) GetPendingByUserID(ctx context.Context, userID, channelID string, limit int64) ([]chat_models.ScheduledMessage, error) {
+	filter := bson.M{
+		"user_id": userID,
+		"status":  chat_models.PendingScheduledStatus,
+	}
+	if channelID != "" {
+		filter["channel_id"] = channelID
+	}
+	cur, err := r.mongoDB.Find(
+		ctx,
+		filter,
+		options.Find().SetSort(bson.D{
+			{Key: "send_at", Value: 1},
+		}).SetLimit(limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cur.Close(ctx)
+		if err != nil {
+			r.logger.Err(err)
+			return
+		}
+	}()
+	res := make([]chat_models.ScheduledMessage, 0, cur.RemainingBatchLength())
+	for cur.Next(ctx) {
+		curr := chat_models.ScheduledMessage{}
+		err = cur.Decode(&curr)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, curr)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Cancel cancels the message if it is still pending
+func (r *ScheduledMessageRepoImpl) Cancel(ctx context.Context, id string, updatedAt int64) error {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return custom_errors.ErrInvalidIDType
+	}
+	res, err := r.mongoDB.UpdateOne(ctx, bson.M{
+		"_id":    objID,
+		"status": chat_models.PendingScheduledStatus,
+	}, bson.M{
+		"$set": bson.M{
+			"status":     chat_models.CanceledScheduledStatus,
+			"updated_at": updatedAt,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return custom_errors.ErrScheduledMessageNotPending
+	}
+
+	return nil
+}
+
+// ClaimDue takes the earliest due message and hides it from other schedulers for lease seconds.
+// Returns nil if nothing is due
+func (r *ScheduledMessageRepoImpl) ClaimDue(ctx context.Context, now, lease int64) (*chat_models.ScheduledMessage, error) {
+	res := r.mongoDB.FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"status": chat_models.PendingScheduledStatus,
+			"next_attempt_at": bson.M{
+				"$lte": now,
+			},
+		},
+		bson.M{
+			"$set": bson.M{
+				"next_attempt_at": now + lease,
+			},
+		},
+		options.FindOneAndUpdate().
+			SetSort(bson.D{
+				{Key: "next_attempt_at", Value: 1},
+			}).
+			SetReturnDocument(options.After),
+	)
+	sm := &chat_models.ScheduledMessage{}
+	err := res.Decode(sm)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return sm, nil
+}
+
+// MarkSent fails with ErrScheduledMessageNotPending if the message was canceled or sent by another scheduler,
+// so in a transaction the posted message is rolled back
+func (r *ScheduledMessageRepoImpl) MarkSent(ctx context.Context, id bson.ObjectID, messageID string, sentAt int64) error {
+	res, err := r.mongoDB.UpdateOne(ctx, bson.M{
+		"_id":    id,
+		"status": chat_models.PendingScheduledStatus,
+	}, bson.M{
+		"$set": bson.M{
+			"status":     chat_models.SentScheduledStatus,
+			"message_id": messageID,
+			"updated_at": sentAt,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return custom_errors.ErrScheduledMessageNotPending
+	}
+
+	return nil
+}
+
+// MarkFailed saves attempt result: status, attempts, next attempt time and error
+func (r *ScheduledMessageRepoImpl) MarkFailed(ctx context.Context, sm chat_models.ScheduledMessage) error {
+	_, err := r.mongoDB.UpdateOne(ctx, bson.M{
+		"_id":    sm.ID,
+		"status": chat_models.PendingScheduledStatus,
+	}, bson.M{
+		"$set": bson.M{
+			"status":          sm.Status,
+			"attempts":        sm.Attempts,
+			"next_attempt_at": sm.NextAttemptAt,
+			"last_error":      sm.LastError,
+			"updated_at":      sm.UpdatedAt,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
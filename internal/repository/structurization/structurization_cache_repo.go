@@ -0,0 +1,91 @@
+package structurization_repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+	structurizationmodels "github.com/Petr09Mitin/xrust-beze-back/internal/models/structurization"
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// StructurizationCacheRepo keeps explanations by hash of question and answer
+type StructurizationCacheRepo interface {
+	Get(ctx context.Context, hash string) (string, error)
+	Save(ctx context.Context, hash, structurized string) error
+	// StartInFlight marks the key as being generated, false means it is already generated by someone else.
+	// Marks older than ttl are taken over, so a crashed generation does not block the key forever
+	StartInFlight(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	FinishInFlight(ctx context.Context, key string) error
+}
+
+type StructurizationCacheRepoImpl struct {
+	mongoDB *mongo.Collection
+	logger  zerolog.Logger
+}
+
+func NewStructurizationCacheRepo(mongoDB *mongo.Collection, logger zerolog.Logger) StructurizationCacheRepo {
+	return &StructurizationCacheRepoImpl{
+		mongoDB: mongoDB,
+		logger:  logger,
+	}
+}
+
+func (r *StructurizationCacheRepoImpl) Get(ctx context.Context, hash string) (string, error) {
+	cached := &structurizationmodels.CachedStructurization{}
+	err := r.mongoDB.FindOne(ctx, bson.M{"_id": hash}).Decode(cached)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return "", custom_errors.ErrNotFound
+		}
+		return "", err
+	}
+
+	return cached.Structurized, nil
+}
+
+// Save replaces the cached explanation, so regenerated one is reused next time
+func (r *StructurizationCacheRepoImpl) Save(ctx context.Context, hash, structurized string) error {
+	_, err := r.mongoDB.ReplaceOne(
+		ctx,
+		bson.M{"_id": hash},
+		structurizationmodels.CachedStructurization{
+			Hash:         hash,
+			Structurized: structurized,
+			CreatedAt:    time.Now().Unix(),
+		},
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+func (r *StructurizationCacheRepoImpl) StartInFlight(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	_, err := r.mongoDB.UpdateOne(
+		ctx,
+		bson.M{
+			"_id":        key,
+			"started_at": bson.M{"$lt": now.Add(-ttl).Unix()},
+		},
+		bson.M{"$set": bson.M{"started_at": now.Unix()}},
+		options.UpdateOne().SetUpsert(true),
+	)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			// the fresh mark exists, so the filter did not match and the upsert hit the same _id
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *StructurizationCacheRepoImpl) FinishInFlight(ctx context.Context, key string) error {
+	_, err := r.mongoDB.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
@@ -0,0 +1,57 @@
+package structurization_repo
+
+import (
+	"context"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/rs/zerolog"
+)
+
+type StructurizationPubRepo interface {
+	PublishTask(ctx context.Context, task chat_models.StructurizationTask) error
+	PublishSummaryTask(ctx context.Context, task chat_models.SummaryTask) error
+}
+
+type StructurizationPubRepoImpl struct {
+	p            message.Publisher
+	topic        string
+	summaryTopic string
+	logger       zerolog.Logger
+}
+
+func NewStructurizationPubRepo(p message.Publisher, topic, summaryTopic string, logger zerolog.Logger) StructurizationPubRepo {
+	return &StructurizationPubRepoImpl{
+		p:            p,
+		topic:        topic,
+		summaryTopic: summaryTopic,
+		logger:       logger,
+	}
+}
+
+func (r *StructurizationPubRepoImpl) PublishTask(ctx context.Context, task chat_models.StructurizationTask) error {
+	payload, err := task.Encode()
+	if err != nil {
+		return err
+	}
+	wmMsg := message.NewMessage(
+		watermill.NewUUID(),
+		payload,
+	)
+	wmMsg.SetContext(ctx)
+	return r.p.Publish(r.topic, wmMsg)
+}
+
+func (r *StructurizationPubRepoImpl) PublishSummaryTask(ctx context.Context, task chat_models.SummaryTask) error {
+	payload, err := task.Encode()
+	if err != nil {
+		return err
+	}
+	wmMsg := message.NewMessage(
+		watermill.NewUUID(),
+		payload,
+	)
+	wmMsg.SetContext(ctx)
+	return r.p.Publish(r.summaryTopic, wmMsg)
+}
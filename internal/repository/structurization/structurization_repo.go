@@ -16,6 +16,7 @@ import (
 
 type StructurizationRepository interface {
 	SendStructRequest(ctx context.Context, question, answer string) (*structurizationmodels.StructurizedMessage, error)
+	SendSummaryRequest(ctx context.Context, transcript string) (*structurizationmodels.StructurizedMessage, error)
 }
 
 type StructurizationRepositoryImpl struct {
@@ -33,10 +34,22 @@ func NewStructurizationRepository(cfg *config.GRPCService, logger zerolog.Logger
 }
 
 func (s *StructurizationRepositoryImpl) SendStructRequest(ctx context.Context, question, answer string) (*structurizationmodels.StructurizedMessage, error) {
-	marshalled, err := json.Marshal(structurizationmodels.StructRequest{
+	return s.sendRequest(ctx, structurizationmodels.StructRequest{
 		Query:  question,
 		Answer: answer,
 	})
+}
+
+// SendSummaryRequest asks for Markdown summary of the whole conversation
+func (s *StructurizationRepositoryImpl) SendSummaryRequest(ctx context.Context, transcript string) (*structurizationmodels.StructurizedMessage, error) {
+	return s.sendRequest(ctx, structurizationmodels.StructRequest{
+		Answer: transcript,
+		Mode:   structurizationmodels.SummaryMode,
+	})
+}
+
+func (s *StructurizationRepositoryImpl) sendRequest(ctx context.Context, structReq structurizationmodels.StructRequest) (*structurizationmodels.StructurizedMessage, error) {
+	marshalled, err := json.Marshal(structReq)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("unable to marshal json to structurize")
 		return nil, err
@@ -26,9 +26,11 @@ func NewStudyMaterialPub(pubTopicID string, pub message.Publisher, logger zerolo
 	}
 }
 
-func (s *StudyMaterialPubImpl) PublishAttachmentToParse(_ context.Context, attachment *study_material_models.AttachmentToParse) error {
-	return s.pub.Publish(s.pubTopicID, message.NewMessage(
+func (s *StudyMaterialPubImpl) PublishAttachmentToParse(ctx context.Context, attachment *study_material_models.AttachmentToParse) error {
+	wmMsg := message.NewMessage(
 		watermill.NewUUID(),
 		attachment.Encode(),
-	))
+	)
+	wmMsg.SetContext(ctx)
+	return s.pub.Publish(s.pubTopicID, wmMsg)
 }
@@ -16,6 +16,7 @@ import (
 )
 
 type UserRepo interface {
+	EnsureIndexes(ctx context.Context) error
 	Create(ctx context.Context, user *user_model.User, hashedPassword string) error
 	GetByID(ctx context.Context, id string) (*user_model.User, error)
 	GetByEmail(ctx context.Context, email string) (*user_model.User, error)
@@ -23,12 +24,17 @@ type UserRepo interface {
 	GetByUsername(ctx context.Context, username string) (*user_model.User, error)
 	GetByUsernameWithPassword(ctx context.Context, username string) (*auth_model.RegisterRequest, error)
 	Update(ctx context.Context, user *user_model.User) error
+	UpdateLastActiveAt(ctx context.Context, id string, lastActiveAt time.Time) error
 	Delete(ctx context.Context, id string) error
 	List(ctx context.Context, page, limit int) ([]*user_model.User, error)
 	FindBySkills(ctx context.Context, skillsToLearn []string) ([]*user_model.User, error)
 	FindByUsername(ctx context.Context, currUserID, name string, limit, offset int64) ([]*user_model.User, error)
 	FindBySkillsToShare(ctx context.Context, skills []string, limit, offset int64) ([]*user_model.User, error)
 	FindBySkillsToLearn(ctx context.Context, skills []string, limit, offset int64) ([]*user_model.User, error)
+	Block(ctx context.Context, userID, blockedUserID string) error
+	Unblock(ctx context.Context, userID, blockedUserID string) error
+	GetBlockedByUserIDs(ctx context.Context, userID string) ([]string, error)
+	GetByIDs(ctx context.Context, ids []string) ([]*user_model.User, error)
 }
 
 type userRepository struct {
@@ -45,6 +51,19 @@ func NewUserRepository(db *mongo.Database, timeout time.Duration, logger zerolog
 	}
 }
 
+// EnsureIndexes creates indexes required by the block queries, it is safe to call on every startup
+func (r *userRepository) EnsureIndexes(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		// GetBlockedByUserIDs looks up users whose block lists contain the user
+		Keys:    bson.D{{Key: "blocked_user_ids", Value: 1}},
+		Options: options.Index().SetName("blocked_user_ids"),
+	})
+	return err
+}
+
 func (r *userRepository) Create(ctx context.Context, user *user_model.User, hashedPassword string) error {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
@@ -164,6 +183,127 @@ func (r *userRepository) Update(ctx context.Context, user *user_model.User) erro
 	return err
 }
 
+func (r *userRepository) UpdateLastActiveAt(ctx context.Context, id string, lastActiveAt time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{
+		"$set": bson.M{"last_active_at": lastActiveAt},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return custom_errors.ErrUserNotExists
+	}
+	return nil
+}
+
+func (r *userRepository) Block(ctx context.Context, userID, blockedUserID string) error {
+	return r.updateBlockedUserIDs(ctx, userID, bson.M{
+		"$addToSet": bson.M{"blocked_user_ids": blockedUserID},
+	})
+}
+
+func (r *userRepository) Unblock(ctx context.Context, userID, blockedUserID string) error {
+	return r.updateBlockedUserIDs(ctx, userID, bson.M{
+		"$pull": bson.M{"blocked_user_ids": blockedUserID},
+	})
+}
+
+func (r *userRepository) updateBlockedUserIDs(ctx context.Context, userID string, update bson.M) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	objectID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return custom_errors.ErrUserNotExists
+	}
+	return nil
+}
+
+// GetBlockedByUserIDs returns ids of users who have blocked the user
+func (r *userRepository) GetBlockedByUserIDs(ctx context.Context, userID string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	cursor, err := r.collection.Find(
+		ctx,
+		bson.M{"blocked_user_ids": userID},
+		options.Find().SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			r.logger.Error().Err(err).Msg("failed to close cursor")
+		}
+	}()
+
+	ids := make([]string, 0)
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID bson.ObjectID `bson:"_id"`
+		}
+		if err = cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		ids = append(ids, doc.ID.Hex())
+	}
+	if err = cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *userRepository) GetByIDs(ctx context.Context, ids []string) ([]*user_model.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	objectIDs := make([]bson.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		objectID, err := bson.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, err
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": objectIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			r.logger.Error().Err(err).Msg("failed to close cursor")
+		}
+	}()
+
+	users := make([]*user_model.User, 0, len(ids))
+	if err = cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
 func (r *userRepository) Delete(ctx context.Context, id string) error {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
@@ -26,9 +26,11 @@ func NewVoiceRecognitionPubRepo(p message.Publisher, topic string, logger zerolo
 	}
 }
 
-func (r *VoiceRecognitionPubRepoImpl) PublishMessage(_ context.Context, msg chat_models.Message) error {
-	return r.p.Publish(r.topic, message.NewMessage(
+func (r *VoiceRecognitionPubRepoImpl) PublishMessage(ctx context.Context, msg chat_models.Message) error {
+	wmMsg := message.NewMessage(
 		watermill.NewUUID(),
 		msg.Encode(),
-	))
+	)
+	wmMsg.SetContext(ctx)
+	return r.p.Publish(r.topic, wmMsg)
 }
@@ -0,0 +1,44 @@
+package schedulerd
+
+import (
+	"context"
+	"time"
+
+	"github.com/Petr09Mitin/xrust-beze-back/internal/services/scheduler"
+	"github.com/rs/zerolog"
+)
+
+type SchedulerD struct {
+	schedulerService scheduler.SchedulerService
+	pollInterval     time.Duration
+	batchSize        int
+	logger           zerolog.Logger
+}
+
+func NewSchedulerD(
+	schedulerService scheduler.SchedulerService,
+	pollInterval time.Duration,
+	batchSize int,
+	logger zerolog.Logger,
+) *SchedulerD {
+	return &SchedulerD{
+		schedulerService: schedulerService,
+		pollInterval:     pollInterval,
+		batchSize:        batchSize,
+		logger:           logger,
+	}
+}
+
+// Run posts due messages every poll interval until ctx is canceled
+func (s *SchedulerD) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+	for {
+		s.schedulerService.DispatchDue(ctx, s.batchSize)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package structurizationd
+
+import (
+	"context"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	"github.com/Petr09Mitin/xrust-beze-back/internal/services/structurization"
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/rs/zerolog"
+)
+
+type StructurizationD struct {
+	structurizationService structurization.StructurizationService
+	subTopicID             string
+	summarySubTopicID      string
+	pubTopicID             string
+	router                 *message.Router
+	sub                    message.Subscriber
+	pub                    message.Publisher
+	logger                 zerolog.Logger
+}
+
+func NewStructurizationD(
+	structurizationService structurization.StructurizationService,
+	subTopicID string,
+	summarySubTopicID string,
+	pubTopicID string,
+	router *message.Router,
+	sub message.Subscriber,
+	pub message.Publisher,
+	logger zerolog.Logger,
+) *StructurizationD {
+	return &StructurizationD{
+		structurizationService: structurizationService,
+		subTopicID:             subTopicID,
+		summarySubTopicID:      summarySubTopicID,
+		pubTopicID:             pubTopicID,
+		router:                 router,
+		sub:                    sub,
+		pub:                    pub,
+		logger:                 logger,
+	}
+}
+
+func (s *StructurizationD) Run(ctx context.Context) error {
+	s.registerHandler()
+	return s.router.Run(ctx)
+}
+
+func (s *StructurizationD) GracefulStop() error {
+	return s.router.Close()
+}
+
+func (s *StructurizationD) registerHandler() {
+	s.router.AddHandler(
+		"structurization_handler",
+		s.subTopicID,
+		s.sub,
+		s.pubTopicID,
+		s.pub,
+		s.handleMessage,
+	)
+	s.router.AddHandler(
+		"summary_handler",
+		s.summarySubTopicID,
+		s.sub,
+		s.pubTopicID,
+		s.pub,
+		s.handleSummaryTask,
+	)
+}
+
+// handleMessage publishes the result to the chat message topic, so it is delivered like any other update
+func (s *StructurizationD) handleMessage(msg *message.Message) ([]*message.Message, error) {
+	task, err := chat_models.DecodeToStructurizationTask(msg.Payload)
+	if err != nil {
+		s.logger.Err(err).Msg("failed to decode structurization task")
+		return nil, nil // need to send ack to watermill
+	}
+	s.logger.Info().Str("message_id", task.Message.MessageID).Msg("decoded structurization task")
+	newMsg, err := s.structurizationService.ProcessStructurizationTask(context.Background(), task)
+	if err != nil {
+		s.logger.Err(err).Msg("failed to process structurization task")
+	}
+	// failed status is still sent, so clients stop waiting
+	if newMsg == nil {
+		return nil, nil
+	}
+	return []*message.Message{
+		message.NewMessage(
+			watermill.NewUUID(),
+			newMsg.Encode(),
+		),
+	}, nil
+}
+
+func (s *StructurizationD) handleSummaryTask(msg *message.Message) ([]*message.Message, error) {
+	task, err := chat_models.DecodeToSummaryTask(msg.Payload)
+	if err != nil {
+		s.logger.Err(err).Msg("failed to decode summary task")
+		return nil, nil // need to send ack to watermill
+	}
+	s.logger.Info().Str("channel_id", task.ChannelID).Msg("decoded summary task")
+	newMsgs, err := s.structurizationService.ProcessSummaryTask(context.Background(), task)
+	if err != nil {
+		s.logger.Err(err).Msg("failed to process summary task")
+	}
+	// the summary goes before its pin update, so clients know the pinned message
+	result := make([]*message.Message, 0, len(newMsgs))
+	for _, newMsg := range newMsgs {
+		result = append(result, message.NewMessage(
+			watermill.NewUUID(),
+			newMsg.Encode(),
+		))
+	}
+	return result, nil
+}
@@ -89,13 +89,25 @@ func (f *FileGRPCService) MoveTempFilesToAttachments(ctx context.Context, req *p
 		return nil, status.Error(codes.InvalidArgument, "filenames are empty")
 	}
 
-	err := f.fileService.MoveTempFilesToAttachments(ctx, filenames)
+	attachments, err := f.fileService.MoveTempFilesToAttachments(ctx, filenames)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	pbAttachments := make([]*pb.AttachmentMetadata, 0, len(attachments))
+	for _, attachment := range attachments {
+		pbAttachments = append(pbAttachments, &pb.AttachmentMetadata{
+			Filename:     attachment.Filename,
+			OriginalName: attachment.OriginalName,
+			Size:         attachment.Size,
+			MimeType:     attachment.MimeType,
+			Width:        int32(attachment.Width),
+			Height:       int32(attachment.Height),
+		})
+	}
 	return &pb.MoveTempFilesToAttachmentsResponse{
-		Filenames: filenames,
+		Filenames:   filenames,
+		Attachments: pbAttachments,
 	}, nil
 }
 
@@ -128,3 +140,19 @@ func (f *FileGRPCService) CopyAttachmentToStudyMaterials(ctx context.Context, re
 		Filename: filename,
 	}, nil
 }
+
+func (f *FileGRPCService) CopyAttachments(ctx context.Context, req *pb.CopyAttachmentsRequest) (*pb.CopyAttachmentsResponse, error) {
+	filenames := req.GetFilenames()
+	if len(filenames) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "filenames are empty")
+	}
+
+	newFilenames, err := f.fileService.CopyAttachments(ctx, filenames)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.CopyAttachmentsResponse{
+		Filenames: newFilenames,
+	}, nil
+}
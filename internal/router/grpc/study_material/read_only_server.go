@@ -0,0 +1,24 @@
+package study_material_grpc
+
+import (
+	"context"
+
+	pb "github.com/Petr09Mitin/xrust-beze-back/proto/study_material"
+)
+
+// ReadOnlyStudyMaterialServer exposes only the reads other services need.
+// The rest of the methods (e.g. DeleteStudyMaterial, which trusts author_id of the request) are unimplemented
+type ReadOnlyStudyMaterialServer struct {
+	pb.UnimplementedStudyMaterialServiceServer
+	server *StudyMaterialServer
+}
+
+func NewReadOnlyStudyMaterialServer(server *StudyMaterialServer) *ReadOnlyStudyMaterialServer {
+	return &ReadOnlyStudyMaterialServer{
+		server: server,
+	}
+}
+
+func (s *ReadOnlyStudyMaterialServer) GetStudyMaterialByID(ctx context.Context, req *pb.GetStudyMaterialByIDRequest) (*pb.StudyMaterialResponse, error) {
+	return s.server.GetStudyMaterialByID(ctx, req)
+}
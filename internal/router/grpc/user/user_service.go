@@ -3,6 +3,7 @@ package user_grpc
 import (
 	"context"
 	"errors"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 
@@ -17,6 +18,12 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// InternalMethods доступны только внутренним сервисам с service token: они принимают id любого пользователя
+var InternalMethods = []string{
+	pb.UserService_UpdateLastActiveAt_FullMethodName,
+	pb.UserService_GetBlockedUsers_FullMethodName,
+}
+
 // UserService представляет gRPC сервис для пользователей
 type UserService struct {
 	pb.UnimplementedUserServiceServer
@@ -196,6 +203,41 @@ func (s *UserService) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest)
 	}, nil
 }
 
+// UpdateLastActiveAt обновляет время последней активности пользователя
+func (s *UserService) UpdateLastActiveAt(ctx context.Context, req *pb.UpdateLastActiveAtRequest) (*pb.UpdateLastActiveAtResponse, error) {
+	var lastActiveAt time.Time
+	if req.GetLastActiveAt() != nil {
+		lastActiveAt = req.GetLastActiveAt().AsTime()
+	}
+	err := s.userService.UpdateLastActiveAt(ctx, req.GetId(), lastActiveAt)
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrUserNotExists) {
+			return nil, status.Errorf(codes.NotFound, "user not found: %v", err)
+		}
+		s.logger.Error().Err(err).Msg("update last active at err")
+		return nil, status.Errorf(codes.Internal, "failed to update last active at: %v", err)
+	}
+
+	return &pb.UpdateLastActiveAtResponse{}, nil
+}
+
+// GetBlockedUsers возвращает пользователей, заблокированных пользователем, и тех, кто заблокировал его
+func (s *UserService) GetBlockedUsers(ctx context.Context, req *pb.GetBlockedUsersRequest) (*pb.GetBlockedUsersResponse, error) {
+	blockedUserIDs, blockedByUserIDs, err := s.userService.GetBlockRelations(ctx, req.GetUserId())
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrUserNotExists) {
+			return nil, status.Errorf(codes.NotFound, "user not found: %v", err)
+		}
+		s.logger.Error().Err(err).Msg("get blocked users err")
+		return nil, status.Errorf(codes.Internal, "failed to get blocked users: %v", err)
+	}
+
+	return &pb.GetBlockedUsersResponse{
+		BlockedUserIds:   blockedUserIDs,
+		BlockedByUserIds: blockedByUserIDs,
+	}, nil
+}
+
 // DeleteUser удаляет пользователя
 func (s *UserService) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
 	// Получаем ID авторизованного пользователя из контекста
@@ -4,12 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/Petr09Mitin/xrust-beze-back/internal/middleware"
 	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
 	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
 	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/config"
 	httpparser "github.com/Petr09Mitin/xrust-beze-back/internal/pkg/httpparser"
-	"github.com/Petr09Mitin/xrust-beze-back/internal/router/middleware"
+	ratelimit_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/ratelimit"
+	middleware2 "github.com/Petr09Mitin/xrust-beze-back/internal/router/middleware"
 	chat_service "github.com/Petr09Mitin/xrust-beze-back/internal/services/chat"
+	authpb "github.com/Petr09Mitin/xrust-beze-back/proto/auth"
+	"github.com/ThreeDotsLabs/watermill"
 	"github.com/gin-gonic/gin"
 	"github.com/olahol/melody"
 	"github.com/rs/zerolog"
@@ -20,6 +24,7 @@ import (
 const (
 	userIDQueryParam = "user_id"
 	peerIDQueryParam = "peer_id"
+	searchQueryParam = "q"
 )
 
 type Chat struct {
@@ -28,15 +33,19 @@ type Chat struct {
 	msgSubscriber       *MessageSubscriber
 	voiceRecognitionSub *VoiceRecognitionSubscriber
 	ChatService         chat_service.ChatService
+	authClient          authpb.AuthServiceClient
+	rateLimitRepo       ratelimit_repo.RateLimitRepo
 	logger              zerolog.Logger
 	cfg                 *config.Chat
 }
 
-func NewChat(chatService chat_service.ChatService, msgSub *MessageSubscriber, voiceRecognitionSub *VoiceRecognitionSubscriber, m *melody.Melody, logger zerolog.Logger, cfg *config.Chat) (*Chat, error) {
+func NewChat(chatService chat_service.ChatService, authClient authpb.AuthServiceClient, msgSub *MessageSubscriber, voiceRecognitionSub *VoiceRecognitionSubscriber, rateLimitRepo ratelimit_repo.RateLimitRepo, m *melody.Melody, logger zerolog.Logger, cfg *config.Chat) (*Chat, error) {
 	ch := &Chat{
 		ChatService:         chatService,
+		authClient:          authClient,
 		msgSubscriber:       msgSub,
 		voiceRecognitionSub: voiceRecognitionSub,
+		rateLimitRepo:       rateLimitRepo,
 		M:                   m,
 		logger:              logger,
 		cfg:                 cfg,
@@ -52,15 +61,33 @@ func NewChat(chatService chat_service.ChatService, msgSub *MessageSubscriber, vo
 
 func (ch *Chat) InitRouter() {
 	ch.R = gin.Default()
-	ch.R.Use(middleware.CORSMiddleware())
+	ch.R.Use(middleware2.CORSMiddleware())
 
 	chatGroup := ch.R.Group("/api/v1/chat")
+	chatGroup.Use(middleware.AuthMiddleware(ch.authClient))
 	{
 		chatGroup.GET("/ws", ch.HandleWSConn)
 		chatGroup.GET("/:channelID", ch.HandleGetMessagesByChannelID)
+		chatGroup.GET("/:channelID/export", ch.handleExportChannel)
 		chatGroup.GET("/channels/by-peer", ch.handleGetChannelByUserAndPeerIDs)
 		chatGroup.GET("/channels", ch.HandleGetChannelsByUserID)
 		chatGroup.GET("/messages/:messageID", ch.GetMessagebyID)
+		chatGroup.GET("/messages/:messageID/revisions", ch.handleGetMessageRevisions)
+		chatGroup.GET("/search", ch.handleSearchMessages)
+		chatGroup.GET("/scheduled", ch.handleGetScheduledMessages)
+		chatGroup.POST("/scheduled", ch.handleScheduleMessage)
+		chatGroup.DELETE("/scheduled/:scheduledID", ch.handleCancelScheduledMessage)
+
+		chatGroup.POST("/channels/:channelID/summary", ch.handleRequestChannelSummary)
+		chatGroup.POST("/channels/:channelID/mute", ch.handleMuteChannel)
+		chatGroup.DELETE("/channels/:channelID/mute", ch.handleUnmuteChannel)
+		chatGroup.POST("/channels/group", ch.handleCreateGroupChannel)
+		chatGroup.PUT("/channels/group/:channelID", ch.handleUpdateGroupChannel)
+		chatGroup.POST("/channels/group/:channelID/members", ch.handleAddGroupMembers)
+		chatGroup.DELETE("/channels/group/:channelID/members/:memberID", ch.handleRemoveGroupMember)
+		chatGroup.POST("/channels/group/:channelID/leave", ch.handleLeaveGroupChannel)
+		chatGroup.PUT("/channels/group/:channelID/admins/:memberID", ch.handleAddGroupAdmin)
+		chatGroup.DELETE("/channels/group/:channelID/admins/:memberID", ch.handleRemoveGroupAdmin)
 	}
 }
 
@@ -69,25 +96,26 @@ func (ch *Chat) InitWS() error {
 	ch.voiceRecognitionSub.RegisterHandler()
 	ch.M.HandleConnect(ch.handleNewChatJoin)
 
-	ch.M.HandleDisconnect(func(s *melody.Session) {
-		ch.logger.Println("dis", s.Request)
+	ch.M.HandleDisconnect(ch.handleChatLeave)
+
+	ch.M.HandlePong(func(s *melody.Session) {
+		userID, connID, ok := getSessionConn(s)
+		if !ok {
+			return
+		}
+		ch.ChatService.UserHeartbeat(context.Background(), userID, connID)
 	})
 
 	ch.M.HandleMessage(func(s *melody.Session, msg []byte) {
-		err := ch.handleMessage(s.Request.Context(), msg)
+		parsedMsg, messageID, err := ch.handleMessage(s, msg)
 		if err != nil {
-			data, err := json.Marshal(map[string]string{"error": err.Error()})
-			if err != nil {
-				ch.logger.Error().Err(err).Msg("unable to marshal error")
-				return
-			}
-			err = s.Write(data)
-			if err != nil {
-				ch.logger.Error().Err(err).Msg("unable to write error to response")
-				return
-			}
+			ch.writeFrame(s, chat_models.NewErrorFrame(parsedMsg.RequestID, parsedMsg.Event, err))
 			return
 		}
+		// ack is sent only to clients which track their requests
+		if parsedMsg.RequestID != "" {
+			ch.writeFrame(s, chat_models.NewAckFrame(parsedMsg.RequestID, parsedMsg.Event, messageID))
+		}
 	})
 
 	go func() {
@@ -120,7 +148,25 @@ func (ch *Chat) Start() error {
 }
 
 func (ch *Chat) HandleWSConn(c *gin.Context) {
-	err := ch.M.HandleRequest(c.Writer, c.Request)
+	userID, err := ch.getAuthorizedUserID(c)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+	keys := map[string]any{
+		UserIDSessionParam: userID,
+		ConnIDSessionParam: watermill.NewUUID(),
+	}
+	if since := strings.TrimSpace(c.Query(sinceQueryParam)); since != "" {
+		sinceTs, err := ch.ChatService.ResolveReplaySince(c.Request.Context(), userID, since)
+		if err != nil {
+			custom_errors.WriteHTTPError(c, err)
+			return
+		}
+		// state is set before the upgrade, so live messages are buffered from the very first one
+		keys[ReplaySessionParam] = newReplayState(sinceTs)
+	}
+	err = ch.M.HandleRequestWithKeys(c.Writer, c.Request, keys)
 	if err != nil {
 		ch.logger.Err(err)
 		custom_errors.WriteHTTPError(c, err)
@@ -129,33 +175,100 @@ func (ch *Chat) HandleWSConn(c *gin.Context) {
 }
 
 func (ch *Chat) handleNewChatJoin(s *melody.Session) {
-	userID := strings.TrimSpace(s.Request.URL.Query().Get(userIDQueryParam))
-	s.Set(UserIDSessionParam, userID)
+	userID, ok := getSessionUserID(s)
+	if !ok {
+		// should not happen as the upgrade is rejected before for unauthorized users
+		ch.logger.Error().Msg("ws session has no verified user_id, closing")
+		if err := s.Close(); err != nil {
+			ch.logger.Error().Err(err).Msg("unable to close unauthorized ws session")
+		}
+		return
+	}
+	ch.logger.Info().Str("user_id", userID).Msg("user joined chat")
+	if _, connID, ok := getSessionConn(s); ok {
+		ch.ChatService.UserConnected(context.Background(), userID, connID)
+	}
+	if state, ok := getSessionReplayState(s); ok {
+		// write pump is started only after this handler returns
+		go ch.replayMissedEvents(s.Request.Context(), s, userID, state)
+	}
 }
 
-func (ch *Chat) handleMessage(ctx context.Context, msg []byte) error {
+func (ch *Chat) handleChatLeave(s *melody.Session) {
+	userID, connID, ok := getSessionConn(s)
+	if !ok {
+		return
+	}
+	ch.logger.Info().Str("user_id", userID).Msg("user left chat")
+	// request context may be already canceled when the connection is closed
+	ch.ChatService.UserDisconnected(context.Background(), userID, connID)
+}
+
+// handleMessage dispatches the client frame.
+// Returns the parsed frame (for request_id and event in the response) and id of the affected message
+func (ch *Chat) handleMessage(s *melody.Session, msg []byte) (chat_models.Message, string, error) {
 	parsedMsg := chat_models.Message{}
+	userID, ok := getSessionUserID(s)
+	if !ok {
+		return parsedMsg, "", custom_errors.ErrMissingUserID
+	}
 	err := json.Unmarshal(msg, &parsedMsg)
 	if err != nil {
-
-		return err
+		return parsedMsg, "", fmt.Errorf("%w: %s", custom_errors.ErrInvalidMessage, err.Error())
 	}
-	ch.logger.Println("msg came to server", parsedMsg)
-	switch parsedMsg.Event {
+	// request_id belongs to this connection only and is not passed further
+	msgToProcess := parsedMsg
+	msgToProcess.RequestID = ""
+	// never trust user_id sent by the client - the session is bound to the verified user
+	msgToProcess.UserID = userID
+	ctx := s.Request.Context()
+	err = ch.checkRateLimit(ctx, userID, parsedMsg.Event)
+	if err != nil {
+		return parsedMsg, "", err
+	}
+	messageID := parsedMsg.MessageID
+	ch.logger.Println("msg came to server", msgToProcess)
+	switch msgToProcess.Event {
 	case chat_models.TextMsgEvent:
-		err = ch.ChatService.ProcessTextMessage(ctx, parsedMsg)
+		messageID, err = ch.ChatService.ProcessTextMessage(ctx, msgToProcess)
 	case chat_models.StructurizationEvent:
-		err = ch.ChatService.ProcessStructurizationRequest(ctx, parsedMsg)
+		err = ch.ChatService.ProcessStructurizationRequest(ctx, msgToProcess)
 	case chat_models.VoiceMessageEvent:
-		err = ch.ChatService.ProcessVoiceMessage(ctx, parsedMsg)
+		messageID, err = ch.ChatService.ProcessVoiceMessage(ctx, msgToProcess)
+	case chat_models.ReadEvent:
+		err = ch.ChatService.ProcessReadEvent(ctx, msgToProcess)
+	case chat_models.TypingEvent:
+		err = ch.ChatService.ProcessTypingEvent(ctx, msgToProcess)
+	case chat_models.ReactionEvent:
+		err = ch.ChatService.ProcessReactionEvent(ctx, msgToProcess)
+	case chat_models.PinEvent:
+		err = ch.ChatService.ProcessPinEvent(ctx, msgToProcess)
+	case chat_models.CallOfferEvent, chat_models.CallAnswerEvent, chat_models.CallIceCandidateEvent, chat_models.CallEndEvent:
+		messageID, err = ch.ChatService.ProcessCallEvent(ctx, msgToProcess)
+	case chat_models.ForwardEvent:
+		messageID, err = ch.ChatService.ProcessForwardMessage(ctx, msgToProcess)
+	case chat_models.StudyMaterialEvent:
+		messageID, err = ch.ChatService.ProcessStudyMaterialMessage(ctx, msgToProcess)
 	default:
 		err = custom_errors.ErrInvalidMessageEvent
 	}
 	if err != nil {
-		return err
+		return parsedMsg, "", err
 	}
 
-	return nil
+	return parsedMsg, messageID, nil
+}
+
+func (ch *Chat) writeFrame(s *melody.Session, frame any) {
+	data, err := json.Marshal(frame)
+	if err != nil {
+		ch.logger.Error().Err(err).Msg("unable to marshal ws frame")
+		return
+	}
+	err = s.Write(data)
+	if err != nil {
+		ch.logger.Error().Err(err).Msg("unable to write ws frame")
+	}
 }
 
 func (ch *Chat) Stop() error {
@@ -178,21 +291,28 @@ func (ch *Chat) HandleGetMessagesByChannelID(c *gin.Context) {
 		return
 	}
 
-	limit, offset := httpparser.GetLimitAndOffset(c)
-	messages, err := ch.ChatService.GetMessagesByChatID(c.Request.Context(), channelID, limit, offset)
+	userID, err := ch.getAuthorizedUserID(c)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+
+	before, after, limit := httpparser.GetCursorsAndLimit(c)
+	page, err := ch.ChatService.GetMessagesByChatID(c.Request.Context(), userID, channelID, before, after, limit)
 	if err != nil {
 		custom_errors.WriteHTTPError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
-		"messages": messages,
+		"messages":    page.Messages,
+		"next_cursor": page.NextCursor,
 	})
 }
 
 func (ch *Chat) HandleGetChannelsByUserID(c *gin.Context) {
-	userID := strings.TrimSpace(c.Query(userIDQueryParam))
-	if userID == "" {
-		custom_errors.WriteHTTPError(c, custom_errors.ErrInvalidUserID)
+	userID, err := ch.getAuthorizedUserID(c)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
 		return
 	}
 	limit, offset := httpparser.GetLimitAndOffset(c)
@@ -207,21 +327,26 @@ func (ch *Chat) HandleGetChannelsByUserID(c *gin.Context) {
 }
 
 func (ch *Chat) handleGetChannelByUserAndPeerIDs(c *gin.Context) {
-	userID := strings.TrimSpace(c.Query(userIDQueryParam))
+	userID, err := ch.getAuthorizedUserID(c)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
 	peerID := strings.TrimSpace(c.Query(peerIDQueryParam))
-	if userID == "" || peerID == "" {
+	if peerID == "" {
 		custom_errors.WriteHTTPError(c, custom_errors.ErrNoUserIDOrPeerID)
 		return
 	}
 
-	channel, msgs, err := ch.ChatService.GetChannelByUserAndPeerIDs(c.Request.Context(), userID, peerID)
+	channel, page, err := ch.ChatService.GetChannelByUserAndPeerIDs(c.Request.Context(), userID, peerID)
 	if err != nil {
 		custom_errors.WriteHTTPError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
-		"channel":  channel,
-		"messages": msgs,
+		"channel":     channel,
+		"messages":    page.Messages,
+		"next_cursor": page.NextCursor,
 	})
 }
 
@@ -232,7 +357,13 @@ func (ch *Chat) GetMessagebyID(c *gin.Context) {
 		return
 	}
 
-	message, err := ch.ChatService.GetMessageByID(c.Request.Context(), messageID)
+	userID, err := ch.getAuthorizedUserID(c)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+
+	message, err := ch.ChatService.GetMessageByID(c.Request.Context(), userID, messageID)
 	if err != nil {
 		custom_errors.WriteHTTPError(c, err)
 		return
@@ -242,3 +373,121 @@ func (ch *Chat) GetMessagebyID(c *gin.Context) {
 		"message": message,
 	})
 }
+
+func (ch *Chat) handleGetMessageRevisions(c *gin.Context) {
+	messageID := strings.TrimSpace(c.Param("messageID"))
+	if messageID == "" {
+		custom_errors.WriteHTTPError(c, custom_errors.ErrNoMessageID)
+		return
+	}
+
+	userID, err := ch.getAuthorizedUserID(c)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+
+	revisions, err := ch.ChatService.GetMessageRevisions(c.Request.Context(), userID, messageID)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"revisions": revisions,
+	})
+}
+
+// handleRequestChannelSummary accepts the request, summary message comes through websocket when it is ready
+func (ch *Chat) handleRequestChannelSummary(c *gin.Context) {
+	channelID := strings.TrimSpace(c.Param("channelID"))
+	if channelID == "" {
+		custom_errors.WriteHTTPError(c, custom_errors.ErrNoChannelID)
+		return
+	}
+
+	userID, err := ch.getAuthorizedUserID(c)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+
+	// empty body means the default window
+	var req chat_models.SummaryRequest
+	if c.Request.ContentLength != 0 {
+		if err = c.ShouldBindJSON(&req); err != nil {
+			custom_errors.WriteHTTPError(c, custom_errors.ErrInvalidBody)
+			return
+		}
+	}
+
+	err = ch.ChatService.RequestChannelSummary(c.Request.Context(), userID, channelID, req)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"status": chat_models.SummaryPendingStatus,
+	})
+}
+
+func (ch *Chat) handleMuteChannel(c *gin.Context) {
+	ch.setChannelMuted(c, true)
+}
+
+func (ch *Chat) handleUnmuteChannel(c *gin.Context) {
+	ch.setChannelMuted(c, false)
+}
+
+func (ch *Chat) setChannelMuted(c *gin.Context, muted bool) {
+	channelID := strings.TrimSpace(c.Param("channelID"))
+	if channelID == "" {
+		custom_errors.WriteHTTPError(c, custom_errors.ErrNoChannelID)
+		return
+	}
+
+	userID, err := ch.getAuthorizedUserID(c)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+
+	channel, err := ch.ChatService.SetChannelMuted(c.Request.Context(), userID, channelID, muted)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, channel)
+}
+
+func (ch *Chat) handleSearchMessages(c *gin.Context) {
+	userID, err := ch.getAuthorizedUserID(c)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+	before, _, limit := httpparser.GetCursorsAndLimit(c)
+	page, err := ch.ChatService.SearchMessages(c.Request.Context(), userID, c.Query(searchQueryParam), before, limit)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"results":     page.Results,
+		"next_cursor": page.NextCursor,
+	})
+}
+
+// getAuthorizedUserID returns the user_id of the session owner.
+// user_id query param is still accepted for compatibility, but must match the session owner
+func (ch *Chat) getAuthorizedUserID(c *gin.Context) (string, error) {
+	userID, ok := middleware.GetUserIDFromGinContext(c)
+	if !ok || userID == "" {
+		return "", custom_errors.ErrMissingUserID
+	}
+	queryUserID := strings.TrimSpace(c.Query(userIDQueryParam))
+	if queryUserID != "" && queryUserID != userID {
+		return "", custom_errors.ErrUserIDMismatch
+	}
+	return userID, nil
+}
@@ -0,0 +1,71 @@
+package chat
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	exportFormatQueryParam = "format"
+)
+
+// exportResponseWriter sends the download headers with the first chunk of the export,
+// until then the handler can still respond with an error
+type exportResponseWriter struct {
+	c        *gin.Context
+	format   chat_models.ExportFormat
+	filename string
+	started  bool
+}
+
+func (w *exportResponseWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.c.Header("Content-Type", w.format.ContentType())
+		w.c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, w.filename))
+		w.c.Status(http.StatusOK)
+	}
+	return w.c.Writer.Write(p)
+}
+
+// handleExportChannel streams the whole channel history as a file
+func (ch *Chat) handleExportChannel(c *gin.Context) {
+	channelID := strings.TrimSpace(c.Param("channelID"))
+	if channelID == "" {
+		custom_errors.WriteHTTPError(c, custom_errors.ErrNoChannelID)
+		return
+	}
+
+	userID, err := ch.getAuthorizedUserID(c)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+
+	format, err := chat_models.ParseExportFormat(strings.TrimSpace(c.Query(exportFormatQueryParam)))
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+
+	w := &exportResponseWriter{
+		c:        c,
+		format:   format,
+		filename: fmt.Sprintf("chat-%s.%s", channelID, format),
+	}
+	err = ch.ChatService.ExportChannel(c.Request.Context(), userID, channelID, format, w)
+	if err != nil {
+		if !w.started {
+			custom_errors.WriteHTTPError(c, err)
+			return
+		}
+		// the status is already sent, the client gets a truncated file
+		ch.logger.Error().Err(err).Str("channel_id", channelID).Msg("channel export interrupted")
+		c.Abort()
+	}
+}
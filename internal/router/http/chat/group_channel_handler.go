@@ -0,0 +1,153 @@
+package chat
+
+import (
+	"net/http"
+	"strings"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+	"github.com/gin-gonic/gin"
+)
+
+func (ch *Chat) handleCreateGroupChannel(c *gin.Context) {
+	userID, err := ch.getAuthorizedUserID(c)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+	var req chat_models.GroupChannelRequest
+	if err = c.ShouldBindJSON(&req); err != nil {
+		custom_errors.WriteHTTPError(c, custom_errors.ErrInvalidBody)
+		return
+	}
+
+	channel, err := ch.ChatService.CreateGroupChannel(c.Request.Context(), userID, req)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"channel": channel,
+	})
+}
+
+func (ch *Chat) handleUpdateGroupChannel(c *gin.Context) {
+	userID, channelID, ok := ch.getGroupChannelParams(c)
+	if !ok {
+		return
+	}
+	var req chat_models.GroupChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		custom_errors.WriteHTTPError(c, custom_errors.ErrInvalidBody)
+		return
+	}
+
+	channel, err := ch.ChatService.UpdateGroupChannel(c.Request.Context(), userID, channelID, req)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"channel": channel,
+	})
+}
+
+func (ch *Chat) handleAddGroupMembers(c *gin.Context) {
+	userID, channelID, ok := ch.getGroupChannelParams(c)
+	if !ok {
+		return
+	}
+	var req chat_models.GroupChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		custom_errors.WriteHTTPError(c, custom_errors.ErrInvalidBody)
+		return
+	}
+
+	channel, err := ch.ChatService.AddGroupMembers(c.Request.Context(), userID, channelID, req.UserIDs)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"channel": channel,
+	})
+}
+
+func (ch *Chat) handleRemoveGroupMember(c *gin.Context) {
+	userID, channelID, ok := ch.getGroupChannelParams(c)
+	if !ok {
+		return
+	}
+	memberID := strings.TrimSpace(c.Param("memberID"))
+	if memberID == "" {
+		custom_errors.WriteHTTPError(c, custom_errors.ErrInvalidUserID)
+		return
+	}
+
+	channel, err := ch.ChatService.RemoveGroupMember(c.Request.Context(), userID, channelID, memberID)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"channel": channel,
+	})
+}
+
+func (ch *Chat) handleLeaveGroupChannel(c *gin.Context) {
+	userID, channelID, ok := ch.getGroupChannelParams(c)
+	if !ok {
+		return
+	}
+
+	err := ch.ChatService.LeaveGroupChannel(c.Request.Context(), userID, channelID)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (ch *Chat) handleAddGroupAdmin(c *gin.Context) {
+	ch.handleSetGroupAdmin(c, true)
+}
+
+func (ch *Chat) handleRemoveGroupAdmin(c *gin.Context) {
+	ch.handleSetGroupAdmin(c, false)
+}
+
+func (ch *Chat) handleSetGroupAdmin(c *gin.Context, isAdmin bool) {
+	userID, channelID, ok := ch.getGroupChannelParams(c)
+	if !ok {
+		return
+	}
+	memberID := strings.TrimSpace(c.Param("memberID"))
+	if memberID == "" {
+		custom_errors.WriteHTTPError(c, custom_errors.ErrInvalidUserID)
+		return
+	}
+
+	channel, err := ch.ChatService.SetGroupAdmin(c.Request.Context(), userID, channelID, memberID, isAdmin)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"channel": channel,
+	})
+}
+
+// getGroupChannelParams writes the error response itself and returns ok=false if params are invalid
+func (ch *Chat) getGroupChannelParams(c *gin.Context) (userID, channelID string, ok bool) {
+	userID, err := ch.getAuthorizedUserID(c)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return "", "", false
+	}
+	channelID = strings.TrimSpace(c.Param("channelID"))
+	if channelID == "" {
+		custom_errors.WriteHTTPError(c, custom_errors.ErrNoChannelID)
+		return "", "", false
+	}
+	return userID, channelID, true
+}
@@ -10,6 +10,7 @@ import (
 
 const (
 	UserIDSessionParam = "user_id_session"
+	ConnIDSessionParam = "conn_id_session"
 )
 
 type MessageSubscriber struct {
@@ -58,18 +59,34 @@ func (s *MessageSubscriber) GracefulStop() error {
 }
 
 func (s *MessageSubscriber) sendMessage(_ context.Context, message chat_models.Message) error {
-	messageWithoutReceivers := message
-	messageWithoutReceivers.ReceiverIDs = nil
-	return s.m.BroadcastFilter(messageWithoutReceivers.Encode(), func(sess *melody.Session) bool {
-		userIDData, exist := sess.Get(UserIDSessionParam)
-		if !exist {
-			return false
-		}
-		userID, ok := userIDData.(string)
-		if !ok {
-			return false
-		}
-		_, ok = message.ReceiverIDs[userID]
-		return ok
-	})
+	return broadcastToReceivers(s.m, message)
+}
+
+// getSessionConn returns the verified user and the unique id of this websocket connection
+func getSessionConn(sess *melody.Session) (userID string, connID string, ok bool) {
+	userID, ok = getSessionUserID(sess)
+	if !ok {
+		return "", "", false
+	}
+	connIDData, exist := sess.Get(ConnIDSessionParam)
+	if !exist {
+		return "", "", false
+	}
+	connID, ok = connIDData.(string)
+	if !ok || connID == "" {
+		return "", "", false
+	}
+	return userID, connID, true
+}
+
+func getSessionUserID(sess *melody.Session) (string, bool) {
+	userIDData, exist := sess.Get(UserIDSessionParam)
+	if !exist {
+		return "", false
+	}
+	userID, ok := userIDData.(string)
+	if !ok || userID == "" {
+		return "", false
+	}
+	return userID, true
 }
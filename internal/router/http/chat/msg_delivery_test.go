@@ -0,0 +1,197 @@
+package chat
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	message_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/chat"
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+	"github.com/gorilla/websocket"
+	"github.com/olahol/melody"
+	"github.com/rs/zerolog"
+)
+
+const (
+	testConsumerGroup       = "xb.chat.delivery"
+	testVoiceProcessedTopic = "xb.voice.processed"
+)
+
+// chatInstance is a chat replica wired as in cmd/chat: its own melody sessions and subscribers
+type chatInstance struct {
+	m      *melody.Melody
+	server *httptest.Server
+}
+
+// newChatInstance subscribes the replica to pubSub. GoChannel gives every subscription every message,
+// as kafka does for distinct consumer groups, so consumerGroups records the groups to check they are distinct
+func newChatInstance(t *testing.T, pubSub *gochannel.GoChannel, consumerGroups map[string]struct{}) *chatInstance {
+	t.Helper()
+	m := melody.New()
+	m.Config.MessageBufferSize = SessionMessageBufferSize
+	msgSub, voiceRecognitionSub, err := NewInstanceSubscribers(
+		func(consumerGroup string) (message.Subscriber, error) {
+			consumerGroups[consumerGroup] = struct{}{}
+			return pubSub, nil
+		},
+		testConsumerGroup,
+		testVoiceProcessedTopic,
+		watermill.NewShortUUID(),
+		m,
+		zerolog.Nop(),
+	)
+	if err != nil {
+		t.Fatalf("subscribers: %v", err)
+	}
+	msgSub.RegisterHandler()
+	voiceRecognitionSub.RegisterHandler()
+	go func() {
+		_ = msgSub.Run()
+	}()
+	<-msgSub.router.Running()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = m.HandleRequestWithKeys(w, r, map[string]any{
+			UserIDSessionParam: r.URL.Query().Get("user_id"),
+			ConnIDSessionParam: watermill.NewUUID(),
+		})
+	}))
+	t.Cleanup(func() {
+		server.Close()
+		_ = m.Close()
+		_ = msgSub.GracefulStop()
+	})
+	return &chatInstance{
+		m:      m,
+		server: server,
+	}
+}
+
+func (i *chatInstance) connect(t *testing.T, userID string) *websocket.Conn {
+	t.Helper()
+	sessionsBefore := i.m.Len()
+	url := "ws" + strings.TrimPrefix(i.server.URL, "http") + "?user_id=" + userID
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	deadline := time.Now().Add(5 * time.Second)
+	for i.m.Len() == sessionsBefore {
+		if time.Now().After(deadline) {
+			t.Fatal("session is not registered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return conn
+}
+
+func readMessages(t *testing.T, conn *websocket.Conn, count int) []chat_models.Message {
+	t.Helper()
+	res := make([]chat_models.Message, 0, count)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for len(res) < count {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("read after %d of %d messages: %v", len(res), count, err)
+		}
+		msg, err := chat_models.DecodeToMessage(data)
+		if err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		res = append(res, *msg)
+	}
+	return res
+}
+
+func publish(t *testing.T, pubSub *gochannel.GoChannel, topic string, msg chat_models.Message) {
+	t.Helper()
+	err := pubSub.Publish(topic, message.NewMessage(watermill.NewUUID(), msg.Encode()))
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+}
+
+func TestInstanceSubscribersDeliverToSessionsOfEveryInstance(t *testing.T) {
+	pubSub := gochannel.NewGoChannel(gochannel.Config{}, watermill.NopLogger{})
+	defer pubSub.Close()
+	consumerGroups := make(map[string]struct{})
+	first := newChatInstance(t, pubSub, consumerGroups)
+	second := newChatInstance(t, pubSub, consumerGroups)
+	if len(consumerGroups) != 4 {
+		t.Fatalf("instances share consumer groups: %d groups for 2 instances with 2 subscribers", len(consumerGroups))
+	}
+
+	// receivers are spread over instances, the second instance holds two connections of bob
+	aliceConn := first.connect(t, "alice")
+	bobConns := []*websocket.Conn{
+		second.connect(t, "bob"),
+		second.connect(t, "bob"),
+	}
+	strangerConn := first.connect(t, "stranger")
+
+	tests := []struct {
+		name  string
+		topic string
+		event chat_models.MsgEvent
+	}{
+		{
+			name:  "messages",
+			topic: message_repo.MessagePubTopic,
+			event: chat_models.TextMsgEvent,
+		},
+		{
+			name:  "processed voice",
+			topic: testVoiceProcessedTopic,
+			event: chat_models.VoiceRecognizedEvent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const messagesCount = 20
+			published := make(map[string]struct{}, messagesCount)
+			for i := 0; i < messagesCount; i++ {
+				msg := chat_models.Message{
+					MessageID: watermill.NewUUID(),
+					Event:     tt.event,
+					Type:      chat_models.SendMessageType,
+					ChannelID: "channel",
+					UserID:    "alice",
+					Payload:   "hello",
+				}
+				msg.SetReceiverIDs([]string{"alice", "bob"})
+				publish(t, pubSub, tt.topic, msg)
+				published[msg.MessageID] = struct{}{}
+			}
+
+			for _, conn := range append([]*websocket.Conn{aliceConn}, bobConns...) {
+				received := make(map[string]struct{}, messagesCount)
+				for _, msg := range readMessages(t, conn, messagesCount) {
+					if _, ok := published[msg.MessageID]; !ok {
+						t.Fatalf("unknown message %s", msg.MessageID)
+					}
+					if msg.ReceiverIDs != nil {
+						t.Fatalf("receivers are sent to the client: %v", msg.ReceiverIDs)
+					}
+					received[msg.MessageID] = struct{}{}
+				}
+				if len(received) != messagesCount {
+					t.Fatalf("got %d distinct messages, want %d", len(received), messagesCount)
+				}
+			}
+		})
+	}
+
+	// a failed read breaks the connection, so the stranger is checked once after all deliveries
+	_ = strangerConn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	if _, data, err := strangerConn.ReadMessage(); err == nil {
+		t.Fatalf("message is delivered to not a receiver: %s", data)
+	}
+}
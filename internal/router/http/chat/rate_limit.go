@@ -0,0 +1,55 @@
+package chat
+
+import (
+	"context"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/config"
+)
+
+const (
+	rateLimitTextBucket            = "text"
+	rateLimitVoiceBucket           = "voice"
+	rateLimitStructurizationBucket = "structurization"
+	rateLimitCallBucket            = "call"
+	rateLimitOtherBucket           = "other"
+)
+
+// getRateLimit returns bucket name and its limits for the event, nil limit means no limit
+func (ch *Chat) getRateLimit(event chat_models.MsgEvent) (string, *config.RateLimit) {
+	limits := ch.cfg.RateLimits
+	if limits == nil {
+		return "", nil
+	}
+	switch event {
+	case chat_models.TextMsgEvent, chat_models.ForwardEvent, chat_models.StudyMaterialEvent:
+		return rateLimitTextBucket, limits.Text
+	case chat_models.VoiceMessageEvent:
+		return rateLimitVoiceBucket, limits.Voice
+	case chat_models.StructurizationEvent:
+		return rateLimitStructurizationBucket, limits.Structurization
+	case chat_models.CallOfferEvent, chat_models.CallAnswerEvent, chat_models.CallIceCandidateEvent, chat_models.CallEndEvent:
+		return rateLimitCallBucket, limits.Call
+	default:
+		return rateLimitOtherBucket, limits.Other
+	}
+}
+
+// checkRateLimit takes a token from the user's bucket for the event.
+// Limiter errors are only logged - chat should keep working without redis
+func (ch *Chat) checkRateLimit(ctx context.Context, userID string, event chat_models.MsgEvent) error {
+	bucket, limit := ch.getRateLimit(event)
+	if limit == nil || limit.Rate <= 0 || limit.Burst <= 0 {
+		return nil
+	}
+	allowed, retryAfter, err := ch.rateLimitRepo.Allow(ctx, userID+":"+bucket, limit.Rate, limit.Burst)
+	if err != nil {
+		ch.logger.Error().Err(err).Str("user_id", userID).Str("bucket", bucket).Msg("unable to check rate limit")
+		return nil
+	}
+	if !allowed {
+		return custom_errors.NewRateLimitedError(retryAfter)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package chat
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	chat_service "github.com/Petr09Mitin/xrust-beze-back/internal/services/chat"
+	"github.com/olahol/melody"
+)
+
+const (
+	ReplaySessionParam = "replay_session"
+	sinceQueryParam    = "since"
+
+	// live messages kept while replaying, with more the client is asked to resync
+	maxPendingLiveMessages = 256
+	// SessionMessageBufferSize fits the whole replay: missed events and live messages kept meanwhile
+	// are written to the session at once, and melody drops the session when its buffer is full
+	SessionMessageBufferSize = chat_service.MaxReplayEvents + maxPendingLiveMessages + 64
+)
+
+// replayState holds live messages of the session while missed ones are being replayed,
+// so the client gets them in order: missed first, then live
+type replayState struct {
+	since      int64
+	mu         sync.Mutex
+	done       bool
+	overflowed bool
+	pending    [][]byte
+}
+
+func newReplayState(since int64) *replayState {
+	return &replayState{
+		since: since,
+	}
+}
+
+// bufferIfReplaying keeps msg until replay is finished, returns false if it should be sent right away
+func (r *replayState) bufferIfReplaying(msg []byte) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.done {
+		return false
+	}
+	if len(r.pending) >= maxPendingLiveMessages {
+		r.overflowed = true
+		r.pending = nil
+	}
+	if !r.overflowed {
+		r.pending = append(r.pending, msg)
+	}
+	return true
+}
+
+// finish sends buffered live messages and switches the session to live delivery.
+// If too many of them came during replay, they are dropped and the client is asked to resync instead
+func (r *replayState) finish(s *melody.Session, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.done = true
+	if r.overflowed {
+		resyncEvent := newResyncEvent(userID, r.since)
+		return s.Write(resyncEvent.Encode())
+	}
+	for _, msg := range r.pending {
+		if err := s.Write(msg); err != nil {
+			return err
+		}
+	}
+	r.pending = nil
+	return nil
+}
+
+func getSessionReplayState(sess *melody.Session) (*replayState, bool) {
+	stateData, exist := sess.Get(ReplaySessionParam)
+	if !exist {
+		return nil, false
+	}
+	state, ok := stateData.(*replayState)
+	return state, ok
+}
+
+// broadcastToReceivers sends message to all connections of its receivers.
+// Receivers who muted the channel get a silent copy, so their clients do not notify them
+func broadcastToReceivers(m *melody.Melody, message chat_models.Message) error {
+	messageWithoutReceivers := message
+	messageWithoutReceivers.ReceiverIDs = nil
+	messageWithoutReceivers.MutedReceiverIDs = nil
+	err := broadcastEncoded(m, messageWithoutReceivers.Encode(), func(userID string) bool {
+		_, ok := message.ReceiverIDs[userID]
+		return ok && !slices.Contains(message.MutedReceiverIDs, userID)
+	})
+	if err != nil || len(message.MutedReceiverIDs) == 0 {
+		return err
+	}
+	messageWithoutReceivers.Silent = true
+	return broadcastEncoded(m, messageWithoutReceivers.Encode(), func(userID string) bool {
+		_, ok := message.ReceiverIDs[userID]
+		return ok && slices.Contains(message.MutedReceiverIDs, userID)
+	})
+}
+
+func broadcastEncoded(m *melody.Melody, encoded []byte, isReceiver func(userID string) bool) error {
+	return m.BroadcastFilter(encoded, func(sess *melody.Session) bool {
+		userID, ok := getSessionUserID(sess)
+		if !ok || !isReceiver(userID) {
+			return false
+		}
+		if state, ok := getSessionReplayState(sess); ok && state.bufferIfReplaying(encoded) {
+			return false
+		}
+		return true
+	})
+}
+
+// replayMissedEvents sends events the user missed while offline and then switches to live delivery.
+// If there are too many of them, the client is asked to resync over REST
+func (ch *Chat) replayMissedEvents(ctx context.Context, s *melody.Session, userID string, state *replayState) {
+	defer func() {
+		if err := state.finish(s, userID); err != nil {
+			ch.logger.Error().Err(err).Str("user_id", userID).Msg("unable to send buffered live messages")
+		}
+	}()
+	events, resync, err := ch.ChatService.GetMissedEvents(ctx, userID, state.since)
+	if err != nil {
+		ch.logger.Error().Err(err).Str("user_id", userID).Msg("unable to get missed events")
+		resync = true
+	}
+	if resync {
+		resyncEvent := newResyncEvent(userID, state.since)
+		if err = s.Write(resyncEvent.Encode()); err != nil {
+			ch.logger.Error().Err(err).Str("user_id", userID).Msg("unable to send resync event")
+		}
+		return
+	}
+	for _, event := range events {
+		if err = s.Write(event.Encode()); err != nil {
+			ch.logger.Error().Err(err).Str("user_id", userID).Msg("unable to replay missed event")
+			return
+		}
+	}
+}
+
+// newResyncEvent asks the client to refetch history changed since over REST
+func newResyncEvent(userID string, since int64) chat_models.Message {
+	return chat_models.Message{
+		Event:     chat_models.ResyncEvent,
+		Type:      chat_models.SendMessageType,
+		UserID:    userID,
+		CreatedAt: since,
+	}
+}
@@ -0,0 +1,66 @@
+package chat
+
+import (
+	"testing"
+
+	chat_service "github.com/Petr09Mitin/xrust-beze-back/internal/services/chat"
+)
+
+func TestReplayStateBuffersLiveMessages(t *testing.T) {
+	tests := []struct {
+		name           string
+		messages       int
+		finished       bool
+		wantBuffered   bool
+		wantPending    int
+		wantOverflowed bool
+	}{
+		{
+			name:         "kept while replaying",
+			messages:     3,
+			wantBuffered: true,
+			wantPending:  3,
+		},
+		{
+			name:         "up to the cap",
+			messages:     maxPendingLiveMessages,
+			wantBuffered: true,
+			wantPending:  maxPendingLiveMessages,
+		},
+		{
+			name:           "over the cap are dropped for resync",
+			messages:       maxPendingLiveMessages + 1,
+			wantBuffered:   true,
+			wantOverflowed: true,
+		},
+		{
+			name:     "sent right away after replay",
+			messages: 3,
+			finished: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := newReplayState(1000)
+			state.done = tt.finished
+			for i := 0; i < tt.messages; i++ {
+				if buffered := state.bufferIfReplaying([]byte("msg")); buffered != tt.wantBuffered {
+					t.Fatalf("message %d: got buffered %t, want %t", i, buffered, tt.wantBuffered)
+				}
+			}
+			if len(state.pending) != tt.wantPending {
+				t.Fatalf("got %d pending, want %d", len(state.pending), tt.wantPending)
+			}
+			if state.overflowed != tt.wantOverflowed {
+				t.Fatalf("got overflowed %t, want %t", state.overflowed, tt.wantOverflowed)
+			}
+		})
+	}
+}
+
+func TestSessionMessageBufferSizeFitsReplay(t *testing.T) {
+	// replayed events and buffered live messages are written to the session at once
+	if need := chat_service.MaxReplayEvents + maxPendingLiveMessages; SessionMessageBufferSize < need {
+		t.Fatalf("session buffer %d does not fit %d replayed and live messages", SessionMessageBufferSize, need)
+	}
+}
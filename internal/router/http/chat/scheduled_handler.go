@@ -0,0 +1,73 @@
+package chat
+
+import (
+	"net/http"
+	"strings"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	channelIDQueryParam = "channel_id"
+)
+
+func (ch *Chat) handleScheduleMessage(c *gin.Context) {
+	userID, err := ch.getAuthorizedUserID(c)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+	var req chat_models.ScheduledMessageRequest
+	if err = c.ShouldBindJSON(&req); err != nil {
+		custom_errors.WriteHTTPError(c, custom_errors.ErrInvalidBody)
+		return
+	}
+
+	scheduled, err := ch.ChatService.ScheduleMessage(c.Request.Context(), userID, req)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"scheduled_message": scheduled,
+	})
+}
+
+func (ch *Chat) handleGetScheduledMessages(c *gin.Context) {
+	userID, err := ch.getAuthorizedUserID(c)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+
+	scheduled, err := ch.ChatService.GetScheduledMessages(c.Request.Context(), userID, strings.TrimSpace(c.Query(channelIDQueryParam)))
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"scheduled_messages": scheduled,
+	})
+}
+
+func (ch *Chat) handleCancelScheduledMessage(c *gin.Context) {
+	userID, err := ch.getAuthorizedUserID(c)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+	scheduledID := strings.TrimSpace(c.Param("scheduledID"))
+	if scheduledID == "" {
+		custom_errors.WriteHTTPError(c, custom_errors.ErrNoMessageID)
+		return
+	}
+
+	err = ch.ChatService.CancelScheduledMessage(c.Request.Context(), userID, scheduledID)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
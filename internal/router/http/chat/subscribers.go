@@ -0,0 +1,37 @@
+package chat
+
+import (
+	infrakafka "github.com/Petr09Mitin/xrust-beze-back/internal/pkg/kafka"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/olahol/melody"
+	"github.com/rs/zerolog"
+)
+
+// SubscriberFactory creates a broker subscriber reading topics in the consumer group
+type SubscriberFactory func(consumerGroup string) (message.Subscriber, error)
+
+// NewInstanceSubscribers wires delivery of one chat replica. Every replica delivers messages only to its own
+// websocket sessions, so its subscribers get consumer groups unique to instanceID and share no partitions with others
+func NewInstanceSubscribers(newSub SubscriberFactory, consumerGroup, voiceProcessedTopic, instanceID string, m *melody.Melody, logger zerolog.Logger) (*MessageSubscriber, *VoiceRecognitionSubscriber, error) {
+	msgsSub, err := newSub(infrakafka.InstanceConsumerGroup(consumerGroup+".messages", instanceID))
+	if err != nil {
+		return nil, nil, err
+	}
+	voiceRecognitionSub, err := newSub(infrakafka.InstanceConsumerGroup(consumerGroup+".voice_processed", instanceID))
+	if err != nil {
+		return nil, nil, err
+	}
+	router, err := infrakafka.NewBrokerRouter()
+	if err != nil {
+		return nil, nil, err
+	}
+	msgSubscriber, err := NewMessageSubscriber(router, msgsSub, m, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	voiceRecognitionSubscriber, err := NewVoiceRecognitionSubscriber(voiceProcessedTopic, router, voiceRecognitionSub, m, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	return msgSubscriber, voiceRecognitionSubscriber, nil
+}
@@ -59,18 +59,5 @@ func (s *VoiceRecognitionSubscriber) GracefulStop() error {
 }
 
 func (s *VoiceRecognitionSubscriber) sendMessage(_ context.Context, message chat_models.Message) error {
-	messageWithoutReceivers := message
-	messageWithoutReceivers.ReceiverIDs = nil
-	return s.m.BroadcastFilter(messageWithoutReceivers.Encode(), func(sess *melody.Session) bool {
-		userIDData, exist := sess.Get(UserIDSessionParam)
-		if !exist {
-			return false
-		}
-		userID, ok := userIDData.(string)
-		if !ok {
-			return false
-		}
-		_, ok = message.ReceiverIDs[userID]
-		return ok
-	})
+	return broadcastToReceivers(s.m, message)
 }
@@ -52,7 +52,7 @@ func (f *FileHandler) UploadTempFile(c *gin.Context) {
 		}
 	}()
 
-	filename, err := f.fileService.UploadTempFile(c.Request.Context(), filepath)
+	filename, err := f.fileService.UploadTempFile(c.Request.Context(), filepath, ff.Filename)
 	if err != nil {
 		f.logger.Error().Err(err).Msg("upload file error")
 		custom_errors.WriteHTTPError(c, err)
@@ -52,6 +52,9 @@ func NewUserHandler(router *gin.Engine, userService user_service.UserService, au
 		secure.GET("/by-name", handler.FindByUsername)
 		secure.GET("/by-skills-to-share", handler.FindBySkillsToShare)
 		secure.GET("/by-skills-to-learn", handler.FindBySkillsToLearn)
+		secure.GET("/blocks", handler.GetBlockedUsers)
+		secure.POST("/blocks/:id", handler.BlockUser)
+		secure.DELETE("/blocks/:id", handler.UnblockUser)
 
 		secure.PUT("/:id", handler.Update)
 		secure.DELETE("/:id", handler.Delete)
@@ -219,6 +222,48 @@ func (h *UserHandler) DeleteReview(c *gin.Context) {
 	c.JSON(http.StatusNoContent, gin.H{"message": "review deleted successfully"})
 }
 
+func (h *UserHandler) BlockUser(c *gin.Context) {
+	userID, ok := middleware.GetUserIDFromGinContext(c)
+	if !ok {
+		custom_errors.WriteHTTPError(c, custom_errors.ErrMissingUserID)
+		return
+	}
+	blockedUserID := strings.TrimSpace(c.Param("id"))
+	if err := h.userService.BlockUser(c.Request.Context(), userID, blockedUserID); err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "user blocked successfully"})
+}
+
+func (h *UserHandler) UnblockUser(c *gin.Context) {
+	userID, ok := middleware.GetUserIDFromGinContext(c)
+	if !ok {
+		custom_errors.WriteHTTPError(c, custom_errors.ErrMissingUserID)
+		return
+	}
+	blockedUserID := strings.TrimSpace(c.Param("id"))
+	if err := h.userService.UnblockUser(c.Request.Context(), userID, blockedUserID); err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "user unblocked successfully"})
+}
+
+func (h *UserHandler) GetBlockedUsers(c *gin.Context) {
+	userID, ok := middleware.GetUserIDFromGinContext(c)
+	if !ok {
+		custom_errors.WriteHTTPError(c, custom_errors.ErrMissingUserID)
+		return
+	}
+	users, err := h.userService.GetBlockedUsers(c.Request.Context(), userID)
+	if err != nil {
+		custom_errors.WriteHTTPError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"users": users})
+}
+
 // Получение списка пользователей
 func (h *UserHandler) List(c *gin.Context) {
 	ctx := c.Request.Context()
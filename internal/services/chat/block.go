@@ -0,0 +1,138 @@
+package chat_service
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+	pb "github.com/Petr09Mitin/xrust-beze-back/proto/user"
+)
+
+const (
+	// blocks made in the user service reach chat after this time at most
+	blockRelationsTTL = 30 * time.Second
+	// outdated relations are still used while the user service is unavailable
+	blockRelationsMaxStale  = 10 * time.Minute
+	maxCachedBlockRelations = 10000
+)
+
+// blockRelations are stored in the user service: users blocked by the user and users who have blocked the user.
+// err is set when they are unknown, then only checks of direct peers fail
+type blockRelations struct {
+	userID    string
+	blocked   []string
+	blockedBy []string
+	err       error
+}
+
+type cachedBlockRelations struct {
+	relations blockRelations
+	fetchedAt time.Time
+}
+
+// blockRelationsCache saves a user service call on every message of the user
+type blockRelationsCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedBlockRelations
+}
+
+func newBlockRelationsCache() *blockRelationsCache {
+	return &blockRelationsCache{
+		entries: make(map[string]cachedBlockRelations),
+	}
+}
+
+func (bc *blockRelationsCache) get(userID string, maxAge time.Duration) (blockRelations, bool) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	cached, ok := bc.entries[userID]
+	if !ok || time.Since(cached.fetchedAt) > maxAge {
+		return blockRelations{}, false
+	}
+	return cached.relations, true
+}
+
+func (bc *blockRelationsCache) set(relations blockRelations) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	if len(bc.entries) >= maxCachedBlockRelations {
+		for userID, cached := range bc.entries {
+			if time.Since(cached.fetchedAt) > blockRelationsMaxStale {
+				delete(bc.entries, userID)
+			}
+		}
+	}
+	bc.entries[relations.userID] = cachedBlockRelations{
+		relations: relations,
+		fetchedAt: time.Now(),
+	}
+}
+
+// getBlockRelations never fails: if the user service is unavailable and nothing is cached,
+// the relations are unknown and checks that need them return ErrBlockedUsersUnavailable
+func (c *ChatServiceImpl) getBlockRelations(ctx context.Context, userID string) blockRelations {
+	if relations, ok := c.blocksCache.get(userID, blockRelationsTTL); ok {
+		return relations
+	}
+	res, err := c.userService.GetBlockedUsers(ctx, &pb.GetBlockedUsersRequest{
+		UserId: userID,
+	})
+	if err != nil {
+		c.logger.Error().Err(err).Str("user_id", userID).Msg("unable to get blocked users")
+		if relations, ok := c.blocksCache.get(userID, blockRelationsMaxStale); ok {
+			return relations
+		}
+		return blockRelations{
+			userID: userID,
+			err:    custom_errors.ErrBlockedUsersUnavailable,
+		}
+	}
+	relations := blockRelations{
+		userID:    userID,
+		blocked:   res.GetBlockedUserIds(),
+		blockedBy: res.GetBlockedByUserIds(),
+	}
+	c.blocksCache.set(relations)
+	return relations
+}
+
+// checkNotBlocked returns ErrUserBlocked if the user and any of peers are blocked in either direction
+func (c *ChatServiceImpl) checkNotBlocked(ctx context.Context, userID string, peerIDs []string) error {
+	return c.getBlockRelations(ctx, userID).checkPeers(peerIDs)
+}
+
+// checkPeers returns ErrUserBlocked if the user has blocked any of peers or was blocked by them
+func (b blockRelations) checkPeers(peerIDs []string) error {
+	for _, peerID := range peerIDs {
+		if peerID == b.userID {
+			continue
+		}
+		if b.err != nil {
+			return b.err
+		}
+		if slices.Contains(b.blocked, peerID) || slices.Contains(b.blockedBy, peerID) {
+			return custom_errors.ErrUserBlocked
+		}
+	}
+	return nil
+}
+
+// checkChannel refuses direct conversations between blocked users.
+// Group channels are shared, so there messages are only hidden from members who blocked the sender
+func (b blockRelations) checkChannel(channel chat_models.Channel) error {
+	if channel.IsGroup() {
+		return nil
+	}
+	return b.checkPeers(channel.UserIDs)
+}
+
+// excludeReceivers removes members who have blocked the user from receivers of the message.
+// While the relations are unknown the message reaches every member
+func (b blockRelations) excludeReceivers(msg *chat_models.Message) {
+	for _, blockedByID := range b.blockedBy {
+		delete(msg.ReceiverIDs, blockedByID)
+	}
+}
@@ -0,0 +1,173 @@
+package chat_service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+	pb "github.com/Petr09Mitin/xrust-beze-back/proto/user"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+)
+
+func TestBlockRelationsCheckChannel(t *testing.T) {
+	direct := chat_models.Channel{ID: "direct", UserIDs: []string{"alice", "bob"}}
+	group := chat_models.Channel{ID: "group", Type: chat_models.GroupChannelType, UserIDs: []string{"alice", "bob", "carol"}}
+	tests := []struct {
+		name      string
+		relations blockRelations
+		channel   chat_models.Channel
+		wantErr   error
+	}{
+		{
+			name:      "no blocks",
+			relations: blockRelations{userID: "alice"},
+			channel:   direct,
+		},
+		{
+			name:      "user blocked the peer",
+			relations: blockRelations{userID: "alice", blocked: []string{"bob"}},
+			channel:   direct,
+			wantErr:   custom_errors.ErrUserBlocked,
+		},
+		{
+			name:      "peer blocked the user",
+			relations: blockRelations{userID: "alice", blockedBy: []string{"bob"}},
+			channel:   direct,
+			wantErr:   custom_errors.ErrUserBlocked,
+		},
+		{
+			name:      "blocks of users outside the channel",
+			relations: blockRelations{userID: "alice", blocked: []string{"carol"}, blockedBy: []string{"dave"}},
+			channel:   direct,
+		},
+		{
+			name:      "group is shared with blocked members",
+			relations: blockRelations{userID: "alice", blocked: []string{"bob"}, blockedBy: []string{"carol"}},
+			channel:   group,
+		},
+		{
+			name:      "unknown relations in direct channel",
+			relations: blockRelations{userID: "alice", err: custom_errors.ErrBlockedUsersUnavailable},
+			channel:   direct,
+			wantErr:   custom_errors.ErrBlockedUsersUnavailable,
+		},
+		{
+			name:      "unknown relations in group",
+			relations: blockRelations{userID: "alice", err: custom_errors.ErrBlockedUsersUnavailable},
+			channel:   group,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.relations.checkChannel(tt.channel)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBlockRelationsExcludeReceivers(t *testing.T) {
+	tests := []struct {
+		name          string
+		relations     blockRelations
+		receivers     []string
+		wantReceivers []string
+	}{
+		{
+			name:          "members who blocked the user are excluded",
+			relations:     blockRelations{userID: "alice", blockedBy: []string{"carol"}},
+			receivers:     []string{"alice", "bob", "carol"},
+			wantReceivers: []string{"alice", "bob"},
+		},
+		{
+			name:          "members blocked by the user still receive",
+			relations:     blockRelations{userID: "alice", blocked: []string{"bob"}},
+			receivers:     []string{"alice", "bob", "carol"},
+			wantReceivers: []string{"alice", "bob", "carol"},
+		},
+		{
+			name:          "blocks of non-receivers are ignored",
+			relations:     blockRelations{userID: "alice", blockedBy: []string{"dave"}},
+			receivers:     []string{"alice", "bob"},
+			wantReceivers: []string{"alice", "bob"},
+		},
+		{
+			name:          "unknown relations exclude nobody",
+			relations:     blockRelations{userID: "alice", err: custom_errors.ErrBlockedUsersUnavailable},
+			receivers:     []string{"alice", "bob"},
+			wantReceivers: []string{"alice", "bob"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := chat_models.Message{}
+			msg.SetReceiverIDs(tt.receivers)
+			tt.relations.excludeReceivers(&msg)
+			got := make([]string, 0, len(msg.ReceiverIDs))
+			for userID := range msg.ReceiverIDs {
+				got = append(got, userID)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.wantReceivers) {
+				t.Fatalf("got receivers %v, want %v", got, tt.wantReceivers)
+			}
+		})
+	}
+}
+
+type fakeUserService struct {
+	UserService
+	res   *pb.GetBlockedUsersResponse
+	err   error
+	calls int
+}
+
+func (s *fakeUserService) GetBlockedUsers(_ context.Context, _ *pb.GetBlockedUsersRequest, _ ...grpc.CallOption) (*pb.GetBlockedUsersResponse, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.res, nil
+}
+
+func TestGetBlockRelationsCache(t *testing.T) {
+	userService := &fakeUserService{res: &pb.GetBlockedUsersResponse{
+		BlockedUserIds:   []string{"bob"},
+		BlockedByUserIds: []string{"carol"},
+	}}
+	c := &ChatServiceImpl{
+		userService: userService,
+		blocksCache: newBlockRelationsCache(),
+		logger:      zerolog.Nop(),
+	}
+
+	first := c.getBlockRelations(context.Background(), "alice")
+	if first.err != nil || !slices.Equal(first.blocked, []string{"bob"}) || !slices.Equal(first.blockedBy, []string{"carol"}) {
+		t.Fatalf("got %+v", first)
+	}
+	c.getBlockRelations(context.Background(), "alice")
+	if userService.calls != 1 {
+		t.Fatalf("got %d user service calls, want cached relations", userService.calls)
+	}
+
+	// outdated relations are used while the user service is down
+	userService.err = errors.New("unavailable")
+	c.blocksCache.entries["alice"] = cachedBlockRelations{
+		relations: first,
+		fetchedAt: c.blocksCache.entries["alice"].fetchedAt.Add(-2 * blockRelationsTTL),
+	}
+	stale := c.getBlockRelations(context.Background(), "alice")
+	if userService.calls != 2 || stale.err != nil || !slices.Equal(stale.blockedBy, []string{"carol"}) {
+		t.Fatalf("got %+v after %d calls, want stale relations", stale, userService.calls)
+	}
+
+	unknown := c.getBlockRelations(context.Background(), "bob")
+	if !errors.Is(unknown.err, custom_errors.ErrBlockedUsersUnavailable) {
+		t.Fatalf("got %v, want ErrBlockedUsersUnavailable", unknown.err)
+	}
+}
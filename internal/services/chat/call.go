@@ -0,0 +1,316 @@
+package chat_service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+	"github.com/google/uuid"
+)
+
+const (
+	// unanswered call does not block the channel longer than callRingTimeout
+	callRingTimeout = time.Minute
+	// active call without end event (e.g. both clients crashed) does not block the channel longer than maxCallDuration
+	maxCallDuration = 12 * time.Hour
+	maxCallIDLen    = 64
+	// stale calls are finished by the sweep at most this time after they became stale
+	staleCallsSweepInterval = 15 * time.Second
+	staleCallsBatchSize     = 100
+)
+
+// ProcessCallEvent handles WebRTC signaling. Signals are relayed only to members of the channel:
+// to peer_id if it is set, otherwise to everyone except the sender.
+// Offer and end change the call state of the channel and are saved in history as call records.
+// Returns id of the call record if it was created
+func (c *ChatServiceImpl) ProcessCallEvent(ctx context.Context, msg chat_models.Message) (string, error) {
+	if msg.ChannelID == "" {
+		return "", custom_errors.ErrNoChannelID
+	}
+	if msg.Signal == nil {
+		return "", custom_errors.ErrInvalidCallSignal
+	}
+	channel, err := c.channelRepo.GetChannelByID(ctx, msg.ChannelID)
+	if err != nil {
+		return "", err
+	}
+	if err = checkChannelMember(channel, msg.UserID); err != nil {
+		return "", err
+	}
+	if msg.PeerID != "" {
+		if err = checkChannelMember(channel, msg.PeerID); err != nil {
+			return "", err
+		}
+	}
+
+	switch msg.Event {
+	case chat_models.CallOfferEvent:
+		return c.startCall(ctx, msg, channel)
+	case chat_models.CallAnswerEvent:
+		return "", c.answerCall(ctx, msg, channel)
+	case chat_models.CallIceCandidateEvent:
+		return "", c.relayIceCandidate(ctx, msg, channel)
+	case chat_models.CallEndEvent:
+		return c.endCall(ctx, msg, channel)
+	default:
+		return "", custom_errors.ErrInvalidMessageEvent
+	}
+}
+
+// startCall saves the call-start record and rings other members.
+// Caller may set call_id to send ICE candidates before the record is delivered
+func (c *ChatServiceImpl) startCall(ctx context.Context, msg chat_models.Message, channel chat_models.Channel) (string, error) {
+	signal := *msg.Signal
+	if signal.SDP == "" || len(signal.CallID) > maxCallIDLen {
+		return "", custom_errors.ErrInvalidCallSignal
+	}
+	switch signal.Media {
+	case "":
+		signal.Media = chat_models.AudioCallMedia
+	case chat_models.AudioCallMedia, chat_models.VideoCallMedia:
+	default:
+		return "", custom_errors.ErrInvalidCallSignal
+	}
+	if signal.CallID == "" {
+		signal.CallID = uuid.NewString()
+	}
+	blocks := c.getBlockRelations(ctx, msg.UserID)
+	if err := blocks.checkChannel(channel); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	call := chat_models.CallState{
+		CallID:    signal.CallID,
+		CallerID:  msg.UserID,
+		Media:     signal.Media,
+		Status:    chat_models.CallRingingStatus,
+		StartedAt: now.Unix(),
+	}
+	staleRingingBefore, staleActiveBefore := now.Add(-callRingTimeout).Unix(), now.Add(-maxCallDuration).Unix()
+	var record chat_models.Message
+	err := c.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if prev := channel.Call; prev != nil && isStaleCall(*prev, staleRingingBefore, staleActiveBefore) {
+			if err := c.finishStaleCall(ctx, channel, *prev, now.Unix()); err != nil {
+				return err
+			}
+		}
+		_, err := c.channelRepo.StartCall(ctx, channel.ID, call, staleRingingBefore, staleActiveBefore)
+		if err != nil {
+			return err
+		}
+		record, err = c.saveCallRecord(ctx, channel, call, msg.UserID)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	offer := newCallSignalEvent(msg, channel, signal)
+	blocks.excludeReceivers(&offer)
+	if err = c.msgPubRepo.PublishEphemeral(ctx, offer); err != nil {
+		c.logger.Error().Err(err).Str("channel_id", channel.ID).Str("call_id", call.CallID).Msg("unable to publish call offer")
+		return "", err
+	}
+	return record.MessageID, nil
+}
+
+func (c *ChatServiceImpl) answerCall(ctx context.Context, msg chat_models.Message, channel chat_models.Channel) error {
+	if msg.Signal.SDP == "" {
+		return custom_errors.ErrInvalidCallSignal
+	}
+	call, err := getChannelCall(channel, msg.Signal.CallID)
+	if err != nil {
+		return err
+	}
+	if call.CallerID == msg.UserID {
+		return custom_errors.ErrCannotAnswerOwnCall
+	}
+	_, err = c.channelRepo.UpdateCallStatus(ctx, channel.ID, call.CallID, chat_models.CallRingingStatus, chat_models.CallActiveStatus, time.Now().Unix())
+	if err != nil {
+		return err
+	}
+
+	// the answer is meant for the caller, unless the client says otherwise
+	if msg.PeerID == "" {
+		msg.PeerID = call.CallerID
+	}
+	return c.msgPubRepo.PublishEphemeral(ctx, newCallSignalEvent(msg, channel, *msg.Signal))
+}
+
+func (c *ChatServiceImpl) relayIceCandidate(ctx context.Context, msg chat_models.Message, channel chat_models.Channel) error {
+	if len(msg.Signal.Candidate) == 0 {
+		return custom_errors.ErrInvalidCallSignal
+	}
+	call, err := getChannelCall(channel, msg.Signal.CallID)
+	if err != nil {
+		return err
+	}
+	if call.IsFinished() {
+		return custom_errors.ErrCallNotFound
+	}
+	return c.msgPubRepo.PublishEphemeral(ctx, newCallSignalEvent(msg, channel, *msg.Signal))
+}
+
+// endCall finishes the call and saves the call-end record with duration.
+// Ringing call becomes missed if the caller hangs up, and ended if it is declined
+func (c *ChatServiceImpl) endCall(ctx context.Context, msg chat_models.Message, channel chat_models.Channel) (string, error) {
+	call, err := getChannelCall(channel, msg.Signal.CallID)
+	if err != nil {
+		return "", err
+	}
+	status := chat_models.CallEndedStatus
+	switch call.Status {
+	case chat_models.CallRingingStatus:
+		if call.CallerID == msg.UserID {
+			status = chat_models.CallMissedStatus
+		}
+	case chat_models.CallActiveStatus:
+	default:
+		return "", custom_errors.ErrCallNotFound
+	}
+
+	var record chat_models.Message
+	err = c.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		newChannel, err := c.channelRepo.UpdateCallStatus(ctx, channel.ID, call.CallID, call.Status, status, time.Now().Unix())
+		if err != nil {
+			return err
+		}
+		record, err = c.saveCallRecord(ctx, channel, *newChannel.Call, msg.UserID)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signal := chat_models.CallSignal{
+		CallID: call.CallID,
+		Media:  call.Media,
+		Status: status,
+	}
+	if err = c.msgPubRepo.PublishEphemeral(ctx, newCallSignalEvent(msg, channel, signal)); err != nil {
+		// the record is already delivered to everyone through the outbox
+		c.logger.Error().Err(err).Str("channel_id", channel.ID).Str("call_id", call.CallID).Msg("unable to publish call end")
+	}
+	return record.MessageID, nil
+}
+
+// RunStaleCallsSweep finishes stale calls every sweep interval until ctx is canceled.
+// Every chat instance may run it, finishing is conditional so each call gets one record
+func (c *ChatServiceImpl) RunStaleCallsSweep(ctx context.Context) {
+	ticker := time.NewTicker(staleCallsSweepInterval)
+	defer ticker.Stop()
+	for {
+		c.finishStaleCalls(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// finishStaleCalls ends calls nobody answered within callRingTimeout and active ones
+// which outlived maxCallDuration, so they do not stay unfinished until the next offer
+func (c *ChatServiceImpl) finishStaleCalls(ctx context.Context) {
+	now := time.Now()
+	channels, err := c.channelRepo.GetChannelsWithStaleCalls(ctx, now.Add(-callRingTimeout).Unix(), now.Add(-maxCallDuration).Unix(), staleCallsBatchSize)
+	if err != nil {
+		c.logger.Error().Err(err).Msg("unable to get channels with stale calls")
+		return
+	}
+	for _, channel := range channels {
+		if ctx.Err() != nil {
+			return
+		}
+		err = c.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+			return c.finishStaleCall(ctx, channel, *channel.Call, now.Unix())
+		})
+		if err != nil {
+			c.logger.Error().Err(err).Str("channel_id", channel.ID).Str("call_id", channel.Call.CallID).Msg("unable to finish stale call")
+		}
+	}
+}
+
+// finishStaleCall marks the unanswered call as missed or the abandoned one as ended and saves its record,
+// so the call is not silently replaced by the next offer
+func (c *ChatServiceImpl) finishStaleCall(ctx context.Context, channel chat_models.Channel, call chat_models.CallState, at int64) error {
+	status := chat_models.CallEndedStatus
+	if call.Status == chat_models.CallRingingStatus {
+		status = chat_models.CallMissedStatus
+	}
+	newChannel, err := c.channelRepo.UpdateCallStatus(ctx, channel.ID, call.CallID, call.Status, status, at)
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrCallNotFound) {
+			// the call was answered or ended concurrently, StartCall decides whether it can be replaced
+			return nil
+		}
+		return err
+	}
+	_, err = c.saveCallRecord(ctx, channel, *newChannel.Call, call.CallerID)
+	return err
+}
+
+// isStaleCall reports whether the call is ringing since before staleRingingBefore or active since before staleActiveBefore
+func isStaleCall(call chat_models.CallState, staleRingingBefore, staleActiveBefore int64) bool {
+	switch call.Status {
+	case chat_models.CallRingingStatus:
+		return call.StartedAt < staleRingingBefore
+	case chat_models.CallActiveStatus:
+		return call.AnsweredAt < staleActiveBefore
+	default:
+		return false
+	}
+}
+
+// saveCallRecord saves the call in its current state to history and publishes it to all members
+func (c *ChatServiceImpl) saveCallRecord(ctx context.Context, channel chat_models.Channel, call chat_models.CallState, userID string) (chat_models.Message, error) {
+	createdAt := time.Now().Unix()
+	record := chat_models.Message{
+		Event:     chat_models.CallEvent,
+		Type:      chat_models.SendMessageType,
+		ChannelID: channel.ID,
+		UserID:    userID,
+		Call:      chat_models.NewCallInfo(call),
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	inserted, err := c.msgRepo.InsertMessage(ctx, record)
+	if err != nil {
+		return chat_models.Message{}, err
+	}
+	inserted.SetReceiverIDs(channel.UserIDs)
+	inserted.MutedReceiverIDs = channel.MutedUserIDs
+	if err = c.msgPubRepo.PublishMessage(ctx, inserted); err != nil {
+		return chat_models.Message{}, err
+	}
+	return inserted, nil
+}
+
+// getChannelCall returns the current call of the channel if it has the id
+func getChannelCall(channel chat_models.Channel, callID string) (chat_models.CallState, error) {
+	if callID == "" || channel.Call == nil || channel.Call.CallID != callID {
+		return chat_models.CallState{}, custom_errors.ErrCallNotFound
+	}
+	return *channel.Call, nil
+}
+
+func newCallSignalEvent(msg chat_models.Message, channel chat_models.Channel, signal chat_models.CallSignal) chat_models.Message {
+	event := chat_models.Message{
+		Event:     msg.Event,
+		Type:      chat_models.SendMessageType,
+		ChannelID: channel.ID,
+		UserID:    msg.UserID,
+		PeerID:    msg.PeerID,
+		Signal:    &signal,
+		CreatedAt: time.Now().Unix(),
+	}
+	if msg.PeerID != "" {
+		event.SetReceiverIDs([]string{msg.PeerID})
+	} else {
+		event.SetReceiverIDs(excludeUserID(channel.UserIDs, msg.UserID))
+	}
+	return event
+}
@@ -0,0 +1,32 @@
+package chat_service
+
+import (
+	"slices"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+)
+
+// checkChannelMember returns ErrNotChannelMember if user does not belong to the channel
+func checkChannelMember(channel chat_models.Channel, userID string) error {
+	if userID == "" || !slices.Contains(channel.UserIDs, userID) {
+		return custom_errors.ErrNotChannelMember
+	}
+	return nil
+}
+
+// checkMessageAuthor returns ErrNotMessageAuthor if user is not the author of the message
+func checkMessageAuthor(msg chat_models.Message, userID string) error {
+	if userID == "" || msg.UserID != userID {
+		return custom_errors.ErrNotMessageAuthor
+	}
+	return nil
+}
+
+// checkMessageInChannel ensures that message really belongs to the channel it is referenced with
+func checkMessageInChannel(msg chat_models.Message, channel chat_models.Channel) error {
+	if msg.ChannelID != channel.ID {
+		return custom_errors.ErrMessageNotInChannel
+	}
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"slices"
 	"time"
 
 	study_material_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/study_material"
@@ -15,122 +17,171 @@ import (
 	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
 	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/config"
 	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/defaults"
+	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/mongotx"
 	channelrepo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/channel"
 	message_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/chat"
+	presence_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/presence"
+	scheduled_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/scheduled"
 	structurization_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/structurization"
 	user_grpc "github.com/Petr09Mitin/xrust-beze-back/internal/router/grpc/user"
+	studymaterialpb "github.com/Petr09Mitin/xrust-beze-back/proto/study_material"
 	pb "github.com/Petr09Mitin/xrust-beze-back/proto/user"
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
 )
 
 type ChatService interface {
-	ProcessTextMessage(ctx context.Context, message chat_models.Message) error
+	ProcessTextMessage(ctx context.Context, message chat_models.Message) (string, error)
 	ProcessStructurizationRequest(ctx context.Context, message chat_models.Message) error
-	ProcessVoiceMessage(ctx context.Context, message chat_models.Message) error
-	GetMessagesByChatID(ctx context.Context, chatID string, limit, offset int64) ([]chat_models.Message, error)
+	ProcessVoiceMessage(ctx context.Context, message chat_models.Message) (string, error)
+	ProcessReadEvent(ctx context.Context, message chat_models.Message) error
+	ProcessTypingEvent(ctx context.Context, message chat_models.Message) error
+	ProcessReactionEvent(ctx context.Context, message chat_models.Message) error
+	ProcessPinEvent(ctx context.Context, message chat_models.Message) error
+	ProcessCallEvent(ctx context.Context, message chat_models.Message) (string, error)
+	ProcessForwardMessage(ctx context.Context, message chat_models.Message) (string, error)
+	ProcessStudyMaterialMessage(ctx context.Context, message chat_models.Message) (string, error)
+	UserConnected(ctx context.Context, userID, connID string)
+	UserHeartbeat(ctx context.Context, userID, connID string)
+	UserDisconnected(ctx context.Context, userID, connID string)
+	GetMessagesByChatID(ctx context.Context, userID, chatID, before, after string, limit int64) (*chat_models.MessagesPage, error)
 	GetChannelsByUserID(ctx context.Context, userID string, limit, offset int64) ([]chat_models.Channel, error)
-	GetChannelByUserAndPeerIDs(ctx context.Context, userID, peerID string) (*chat_models.Channel, []chat_models.Message, error)
-	GetMessageByID(ctx context.Context, messageID string) (*chat_models.Message, error)
+	GetChannelByUserAndPeerIDs(ctx context.Context, userID, peerID string) (*chat_models.Channel, *chat_models.MessagesPage, error)
+	GetMessageByID(ctx context.Context, userID, messageID string) (*chat_models.Message, error)
+	GetMessageRevisions(ctx context.Context, userID, messageID string) ([]chat_models.MessageRevision, error)
+	SearchMessages(ctx context.Context, userID, text, before string, limit int64) (*chat_models.SearchPage, error)
+	ResolveReplaySince(ctx context.Context, userID, since string) (int64, error)
+	GetMissedEvents(ctx context.Context, userID string, since int64) ([]chat_models.Message, bool, error)
+	RequestChannelSummary(ctx context.Context, userID, channelID string, req chat_models.SummaryRequest) error
+	SetChannelMuted(ctx context.Context, userID, channelID string, muted bool) (*chat_models.Channel, error)
+	ExportChannel(ctx context.Context, userID, channelID string, format chat_models.ExportFormat, w io.Writer) error
+	ScheduleMessage(ctx context.Context, userID string, req chat_models.ScheduledMessageRequest) (*chat_models.ScheduledMessage, error)
+	GetScheduledMessages(ctx context.Context, userID, channelID string) ([]chat_models.ScheduledMessage, error)
+	CancelScheduledMessage(ctx context.Context, userID, scheduledMessageID string) error
+	CreateGroupChannel(ctx context.Context, ownerID string, req chat_models.GroupChannelRequest) (*chat_models.Channel, error)
+	UpdateGroupChannel(ctx context.Context, userID, channelID string, req chat_models.GroupChannelRequest) (*chat_models.Channel, error)
+	AddGroupMembers(ctx context.Context, userID, channelID string, memberIDs []string) (*chat_models.Channel, error)
+	RemoveGroupMember(ctx context.Context, userID, channelID, memberID string) (*chat_models.Channel, error)
+	LeaveGroupChannel(ctx context.Context, userID, channelID string) error
+	SetGroupAdmin(ctx context.Context, userID, channelID, adminID string, isAdmin bool) (*chat_models.Channel, error)
+	RunStaleCallsSweep(ctx context.Context)
 }
 
+const (
+	maxMessagesPageSize         = 1000
+	channelPreviewMessagesLimit = 200
+)
+
 type UserService interface {
 	GetUserByID(ctx context.Context, in *pb.GetUserByIDRequest, opts ...grpc.CallOption) (*pb.UserResponse, error)
+	UpdateLastActiveAt(ctx context.Context, in *pb.UpdateLastActiveAtRequest, opts ...grpc.CallOption) (*pb.UpdateLastActiveAtResponse, error)
+	GetBlockedUsers(ctx context.Context, in *pb.GetBlockedUsersRequest, opts ...grpc.CallOption) (*pb.GetBlockedUsersResponse, error)
+}
+
+type StudyMaterialService interface {
+	GetStudyMaterialByID(ctx context.Context, in *studymaterialpb.GetStudyMaterialByIDRequest, opts ...grpc.CallOption) (*studymaterialpb.StudyMaterialResponse, error)
 }
 
 type ChatServiceImpl struct {
-	msgRepo             message_repo.MessageRepo
-	msgPubRepo          message_repo.MessagePubRepo
-	channelRepo         channelrepo.ChannelRepository
-	fileServiceClient   file_client.FileServiceClient
-	structurizationRepo structurization_repo.StructurizationRepository
-	userService         UserService
-	studyMaterialPub    study_material_repo.StudyMaterialPub
-	voiceRecognitionPub voice_recognition_repo.VoiceRecognitionPubRepo
-	cfg                 *config.Chat
-	logger              zerolog.Logger
+	msgRepo                  message_repo.MessageRepo
+	msgPubRepo               message_repo.MessagePubRepo
+	readStateRepo            message_repo.ReadStateRepo
+	channelRepo              channelrepo.ChannelRepository
+	presenceRepo             presence_repo.PresenceRepo
+	fileServiceClient        file_client.FileServiceClient
+	structurizationPub       structurization_repo.StructurizationPubRepo
+	structurizationCacheRepo structurization_repo.StructurizationCacheRepo
+	userService              UserService
+	studyMaterialPub         study_material_repo.StudyMaterialPub
+	studyMaterialService     StudyMaterialService
+	voiceRecognitionPub      voice_recognition_repo.VoiceRecognitionPubRepo
+	scheduledRepo            scheduled_repo.ScheduledMessageRepo
+	txManager                mongotx.TxManager
+	blocksCache              *blockRelationsCache
+	cfg                      *config.Chat
+	logger                   zerolog.Logger
 }
 
 func NewChatService(
 	msgRepo message_repo.MessageRepo,
 	msgPubRepo message_repo.MessagePubRepo,
+	readStateRepo message_repo.ReadStateRepo,
 	channelRepo channelrepo.ChannelRepository,
+	presenceRepo presence_repo.PresenceRepo,
 	fileServiceClient file_client.FileServiceClient,
-	structurizationRepo structurization_repo.StructurizationRepository,
+	structurizationPub structurization_repo.StructurizationPubRepo,
+	structurizationCacheRepo structurization_repo.StructurizationCacheRepo,
 	userService UserService,
 	studyMaterialPub study_material_repo.StudyMaterialPub,
+	studyMaterialService StudyMaterialService,
 	voiceRecognitionPub voice_recognition_repo.VoiceRecognitionPubRepo,
+	scheduledRepo scheduled_repo.ScheduledMessageRepo,
+	txManager mongotx.TxManager,
 	logger zerolog.Logger,
 	cfg *config.Chat) ChatService {
 	return &ChatServiceImpl{
-		msgRepo:             msgRepo,
-		msgPubRepo:          msgPubRepo,
-		channelRepo:         channelRepo,
-		fileServiceClient:   fileServiceClient,
-		structurizationRepo: structurizationRepo,
-		userService:         userService,
-		studyMaterialPub:    studyMaterialPub,
-		voiceRecognitionPub: voiceRecognitionPub,
-		cfg:                 cfg,
-		logger:              logger,
+		msgRepo:                  msgRepo,
+		msgPubRepo:               msgPubRepo,
+		readStateRepo:            readStateRepo,
+		channelRepo:              channelRepo,
+		presenceRepo:             presenceRepo,
+		fileServiceClient:        fileServiceClient,
+		structurizationPub:       structurizationPub,
+		structurizationCacheRepo: structurizationCacheRepo,
+		userService:              userService,
+		studyMaterialPub:         studyMaterialPub,
+		studyMaterialService:     studyMaterialService,
+		voiceRecognitionPub:      voiceRecognitionPub,
+		scheduledRepo:            scheduledRepo,
+		txManager:                txManager,
+		blocksCache:              newBlockRelationsCache(),
+		cfg:                      cfg,
+		logger:                   logger,
 	}
 }
 
-func (c *ChatServiceImpl) ProcessTextMessage(ctx context.Context, msg chat_models.Message) error {
+// ProcessTextMessage saves the change and publishes it to the outbox in the same transaction.
+// Returns id of the persisted message
+func (c *ChatServiceImpl) ProcessTextMessage(ctx context.Context, msg chat_models.Message) (string, error) {
 	var newMsg chat_models.Message
 	var err error
 
 	switch msg.Type {
 	case chat_models.SendMessageType:
 		newMsg, err = c.createTextMessage(ctx, msg)
-		if err != nil {
-			return err
-		}
 	case chat_models.UpdateMessageType:
 		newMsg, err = c.updateTextMessage(ctx, msg)
-		if err != nil {
-			return err
-		}
 	case chat_models.DeleteMessageType:
 		newMsg, err = c.deleteTextMessage(ctx, msg)
-		if err != nil {
-			return err
-		}
 	default:
-		return custom_errors.ErrInvalidMessageType
+		return "", custom_errors.ErrInvalidMessageType
 	}
-
-	if err := c.msgPubRepo.PublishMessage(ctx, newMsg); err != nil {
-		c.logger.Err(err)
-		return custom_errors.ErrBroadcastingTextMessage
+	if err != nil {
+		return "", err
 	}
-	return nil
+
+	return newMsg.MessageID, nil
 }
 
-func (c *ChatServiceImpl) ProcessVoiceMessage(ctx context.Context, msg chat_models.Message) error {
+// ProcessVoiceMessage saves the change and publishes it to the outbox in the same transaction.
+// Returns id of the persisted message
+func (c *ChatServiceImpl) ProcessVoiceMessage(ctx context.Context, msg chat_models.Message) (string, error) {
 	var newMsg chat_models.Message
 	var err error
 
 	switch msg.Type {
 	case chat_models.SendMessageType:
 		newMsg, err = c.createVoiceMessage(ctx, msg)
-		if err != nil {
-			return err
-		}
 	case chat_models.DeleteMessageType:
 		newMsg, err = c.deleteVoiceMessage(ctx, msg)
-		if err != nil {
-			return err
-		}
 	default:
-		return custom_errors.ErrInvalidMessageType
+		return "", custom_errors.ErrInvalidMessageType
 	}
-
-	if err := c.msgPubRepo.PublishMessage(ctx, newMsg); err != nil {
-		c.logger.Err(err)
-		return custom_errors.ErrBroadcastingTextMessage
+	if err != nil {
+		return "", err
 	}
-	return nil
+
+	return newMsg.MessageID, nil
 }
 
 func (c *ChatServiceImpl) ProcessStructurizationRequest(ctx context.Context, message chat_models.Message) error {
@@ -142,13 +193,16 @@ func (c *ChatServiceImpl) ProcessStructurizationRequest(ctx context.Context, mes
 	if err != nil {
 		return err
 	}
-	oldMessage.SetReceiverIDs(channel.UserIDs)
-
-	prevMessages, err := c.msgRepo.GetPreviousMessagesByMessageCreatedAt(ctx, channel.ID, oldMessage.CreatedAt, 1)
+	if err = checkChannelMember(channel, message.UserID); err != nil {
+		return err
+	}
+	if oldMessage.IsDeleted() {
+		return custom_errors.ErrMessageDeleted
+	}
+	question, err := c.getStructurizationQuestion(ctx, *oldMessage)
 	if err != nil {
 		return err
 	}
-	question := c.concatenateMessages(prevMessages)
 	var answer string
 	if oldMessage.RecognizedVoice != "" {
 		answer = oldMessage.RecognizedVoice
@@ -158,28 +212,98 @@ func (c *ChatServiceImpl) ProcessStructurizationRequest(ctx context.Context, mes
 	if answer == "" {
 		return custom_errors.ErrCannotStructurizeEmptyAnswer
 	}
-	structurized, err := c.trySendStructurizationRequest(ctx, question, answer)
-	if err != nil {
-		return err
+	hash := chat_models.StructurizationHash(question, answer)
+	if !message.Regenerate && oldMessage.HasStructurizationFor(hash) {
+		// the stored explanation is returned only to the requester, others already have it
+		return c.publishStoredStructurization(ctx, *oldMessage, message.UserID)
 	}
-	newMsg := *oldMessage
-	newMsg.Structurized = structurized
-	newMsg.UpdatedAt = time.Now().Unix()
-	err = c.msgRepo.UpdateMessage(ctx, newMsg)
+	oldMessage.SetReceiverIDs(channel.UserIDs)
+	if !message.Regenerate {
+		structurized, err := c.structurizationCacheRepo.Get(ctx, hash)
+		if err == nil {
+			return c.saveStructurization(ctx, *oldMessage, structurized, hash)
+		}
+		if !errors.Is(err, custom_errors.ErrNotFound) {
+			c.logger.Error().Err(err).Str("message_id", oldMessage.MessageID).Msg("unable to get cached structurization")
+		}
+	}
+	task := chat_models.StructurizationTask{
+		Message:  *oldMessage,
+		Question: question,
+		Answer:   answer,
+		Hash:     hash,
+	}
+	started, err := c.structurizationCacheRepo.StartInFlight(ctx, task.InFlightKey(), structurizationInFlightTTL)
 	if err != nil {
 		return err
 	}
-	newMsg.Type = chat_models.UpdateMessageType
-	newMsg.Event = chat_models.StructurizationEvent
+	pendingMsg := *oldMessage
+	if started {
+		// explanation takes long, so it is generated by structurizationd and delivered as a usual update
+		err = c.structurizationPub.PublishTask(ctx, task)
+		if err != nil {
+			c.logger.Error().Err(err).Str("message_id", oldMessage.MessageID).Msg("unable to publish structurization task")
+			if err = c.structurizationCacheRepo.FinishInFlight(ctx, task.InFlightKey()); err != nil {
+				c.logger.Error().Err(err).Str("message_id", oldMessage.MessageID).Msg("unable to finish in-flight structurization")
+			}
+			return custom_errors.ErrStructurizationUnavailable
+		}
+	} else {
+		// the same explanation is already generated, its update reaches all members including the requester
+		pendingMsg.SetReceiverIDs([]string{message.UserID})
+	}
 
-	err = c.msgPubRepo.PublishMessage(ctx, newMsg)
+	pendingMsg.Type = chat_models.UpdateMessageType
+	pendingMsg.Type = chat_models.UpdateMessageType
+	pendingMsg.Event = chat_models.StructurizationEvent
+	pendingMsg.Status = chat_models.StructurizationPendingStatus
+	err = c.msgPubRepo.PublishEphemeral(ctx, pendingMsg)
 	if err != nil {
-		return err
+		c.logger.Error().Err(err).Str("message_id", oldMessage.MessageID).Msg("unable to publish structurization pending status")
 	}
 
 	return nil
 }
 
+// getOrCreateMessageChannel returns the channel of the new message: by channel_id,
+// or the direct channel with peer_id which is created on the first message
+func (c *ChatServiceImpl) getOrCreateMessageChannel(ctx context.Context, msg chat_models.Message, blocks blockRelations) (chat_models.Channel, error) {
+	if msg.ChannelID != "" {
+		channel, err := c.channelRepo.GetChannelByID(ctx, msg.ChannelID)
+		if err != nil {
+			return chat_models.Channel{}, err
+		}
+		if err = checkChannelMember(channel, msg.UserID); err != nil {
+			return chat_models.Channel{}, err
+		}
+		if err = blocks.checkChannel(channel); err != nil {
+			return chat_models.Channel{}, err
+		}
+		return channel, nil
+	}
+
+	if msg.UserID == "" || msg.PeerID == "" {
+		return chat_models.Channel{}, custom_errors.ErrInvalidMessage
+	}
+	if err := blocks.checkPeers([]string{msg.PeerID}); err != nil {
+		return chat_models.Channel{}, err
+	}
+	channel, err := c.channelRepo.GetByUserIDs(ctx, []string{msg.UserID, msg.PeerID})
+	if err == nil || !errors.Is(err, custom_errors.ErrNotFound) {
+		return channel, err
+	}
+	created := time.Now().Unix()
+	return c.channelRepo.InsertChannel(ctx, chat_models.Channel{
+		Type: chat_models.DirectChannelType,
+		UserIDs: []string{
+			msg.UserID,
+			msg.PeerID,
+		},
+		Created: created,
+		Updated: created,
+	})
+}
+
 func (c *ChatServiceImpl) createTextMessage(ctx context.Context, msg chat_models.Message) (chat_models.Message, error) {
 	var channel chat_models.Channel
 	var err error
@@ -188,72 +312,76 @@ func (c *ChatServiceImpl) createTextMessage(ctx context.Context, msg chat_models
 		return chat_models.Message{}, custom_errors.ErrInvalidMessage
 	}
 
-	if msg.ChannelID == "" {
-		if msg.UserID == "" || msg.PeerID == "" {
-			return chat_models.Message{}, custom_errors.ErrInvalidMessage
-		}
-		channel, err = c.channelRepo.GetByUserIDs(ctx, []string{msg.UserID, msg.PeerID})
-		if err != nil {
-			if errors.Is(err, custom_errors.ErrNotFound) {
-				created := time.Now().Unix()
-				channel, err = c.channelRepo.InsertChannel(ctx, chat_models.Channel{
-					UserIDs: []string{
-						msg.UserID,
-						msg.PeerID,
-					},
-					Created: created,
-					Updated: created,
-				})
-				if err != nil {
-					return msg, err
-				}
-			} else {
-				return msg, err
-			}
-		}
-	} else {
-		channel, err = c.channelRepo.GetChannelByID(ctx, msg.ChannelID)
+	blocks := c.getBlockRelations(ctx, msg.UserID)
+
+	channel, err = c.getOrCreateMessageChannel(ctx, msg, blocks)
+	if err != nil {
+		return msg, err
+	}
+
+	var replyTo *chat_models.QuotedMessage
+	if msg.ReplyToMessageID != "" {
+		parent, err := c.getReplyParent(ctx, channel, msg.ReplyToMessageID)
 		if err != nil {
-			return msg, err
+			return chat_models.Message{}, err
 		}
+		replyTo = chat_models.NewQuotedMessage(*parent)
 	}
 
 	createdAt := time.Now().Unix()
+	var prevMsgs []chat_models.Message
 	if len(msg.Attachments) > 0 {
-		msg.Attachments, err = c.fileServiceClient.MoveTempFilesToAttachments(ctx, msg.Attachments)
+		msg.Attachments, err = c.fileServiceClient.MoveTempFilesToAttachments(ctx, chat_models.AttachmentFilenames(msg.Attachments))
 		if err != nil {
 			return chat_models.Message{}, err
 		}
-		// publish potential materials for studymateriald to process
-		// if we failed - log and continue
-		prevMsgs, err := c.msgRepo.GetPreviousMessagesByMessageCreatedAt(ctx, channel.ID, createdAt, 10)
+		// previous messages are context for studymateriald, if we failed - log and continue without it
+		prevMsgs, err = c.msgRepo.GetPreviousMessagesByMessageCreatedAt(ctx, channel.ID, createdAt, 10)
 		if err != nil {
 			c.logger.Error().Err(err).Str("channel_id", channel.ID).Msg("unable to get previous messages in studymateriald sending")
-		} else {
-			err = c.publishAttachmentsToProcess(ctx, &msg, prevMsgs)
-			if err != nil {
-				c.logger.Error().Err(err).Any("msg", msg).Msg("unable to publish for studymateriald in create msg")
-			}
 		}
 	} else {
-		msg.Attachments = make([]string, 0)
+		msg.Attachments = make([]chat_models.Attachment, 0)
 	}
 
 	newMsg := chat_models.Message{
-		Event:       chat_models.TextMsgEvent,
-		Type:        msg.Type,
-		ChannelID:   channel.ID,
-		UserID:      msg.UserID,
-		PeerID:      msg.PeerID,
-		Payload:     msg.Payload,
-		Attachments: msg.Attachments,
-		CreatedAt:   createdAt,
-		UpdatedAt:   createdAt,
+		Event:            chat_models.TextMsgEvent,
+		Type:             msg.Type,
+		ChannelID:        channel.ID,
+		UserID:           msg.UserID,
+		PeerID:           msg.PeerID,
+		Payload:          msg.Payload,
+		Attachments:      msg.Attachments,
+		ReplyToMessageID: msg.ReplyToMessageID,
+		ReplyTo:          replyTo,
+		CreatedAt:        createdAt,
+		UpdatedAt:        createdAt,
 	}
 	newMsg.SetReceiverIDs(channel.UserIDs)
-	newMsg, err = c.msgRepo.InsertMessage(ctx, newMsg)
+	newMsg.MutedReceiverIDs = channel.MutedUserIDs
+	blocks.excludeReceivers(&newMsg)
+	err = c.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		inserted, err := c.msgRepo.InsertMessage(ctx, newMsg)
+		if err != nil {
+			return err
+		}
+		// potential materials for studymateriald are saved with the message, so they are not lost either
+		if len(inserted.Attachments) > 0 {
+			err = c.publishAttachmentsToProcess(ctx, &inserted, prevMsgs)
+			if err != nil {
+				return err
+			}
+		}
+		err = c.msgPubRepo.PublishMessage(ctx, inserted)
+		if err != nil {
+			return err
+		}
+		newMsg = inserted
+		return nil
+	})
 	if err != nil {
 		c.logger.Err(err)
+		c.deleteUnsavedAttachments(ctx, newMsg.Attachments)
 		return msg, custom_errors.ErrBroadcastingTextMessage
 	}
 	c.logger.Printf("new message saved: %+v\n", newMsg)
@@ -268,34 +396,20 @@ func (c *ChatServiceImpl) createVoiceMessage(ctx context.Context, msg chat_model
 		return chat_models.Message{}, custom_errors.ErrInvalidMessage
 	}
 
-	if msg.ChannelID == "" {
-		if msg.UserID == "" || msg.PeerID == "" {
-			return chat_models.Message{}, custom_errors.ErrInvalidMessage
-		}
-		channel, err = c.channelRepo.GetByUserIDs(ctx, []string{msg.UserID, msg.PeerID})
-		if err != nil {
-			if errors.Is(err, custom_errors.ErrNotFound) {
-				created := time.Now().Unix()
-				channel, err = c.channelRepo.InsertChannel(ctx, chat_models.Channel{
-					UserIDs: []string{
-						msg.UserID,
-						msg.PeerID,
-					},
-					Created: created,
-					Updated: created,
-				})
-				if err != nil {
-					return msg, err
-				}
-			} else {
-				return msg, err
-			}
-		}
-	} else {
-		channel, err = c.channelRepo.GetChannelByID(ctx, msg.ChannelID)
+	blocks := c.getBlockRelations(ctx, msg.UserID)
+
+	channel, err = c.getOrCreateMessageChannel(ctx, msg, blocks)
+	if err != nil {
+		return msg, err
+	}
+
+	var replyTo *chat_models.QuotedMessage
+	if msg.ReplyToMessageID != "" {
+		parent, err := c.getReplyParent(ctx, channel, msg.ReplyToMessageID)
 		if err != nil {
-			return msg, err
+			return chat_models.Message{}, err
 		}
+		replyTo = chat_models.NewQuotedMessage(*parent)
 	}
 
 	filename, err := c.fileServiceClient.MoveTempFileToVoiceMessages(ctx, msg.Voice)
@@ -305,30 +419,47 @@ func (c *ChatServiceImpl) createVoiceMessage(ctx context.Context, msg chat_model
 
 	createdAt := time.Now().Unix()
 	newMsg := chat_models.Message{
-		Event:         chat_models.VoiceMessageEvent,
-		Type:          msg.Type,
-		ChannelID:     channel.ID,
-		UserID:        msg.UserID,
-		PeerID:        msg.PeerID,
-		Voice:         filename,
-		CreatedAt:     createdAt,
-		UpdatedAt:     createdAt,
-		VoiceDuration: msg.VoiceDuration,
-		Payload:       "",
+		Event:            chat_models.VoiceMessageEvent,
+		Type:             msg.Type,
+		ChannelID:        channel.ID,
+		UserID:           msg.UserID,
+		PeerID:           msg.PeerID,
+		Voice:            filename,
+		CreatedAt:        createdAt,
+		UpdatedAt:        createdAt,
+		VoiceDuration:    msg.VoiceDuration,
+		ReplyToMessageID: msg.ReplyToMessageID,
+		ReplyTo:          replyTo,
+		Payload:          "",
 	}
 	newMsg.SetReceiverIDs(channel.UserIDs)
-	newMsg, err = c.msgRepo.InsertMessage(ctx, newMsg)
+	newMsg.MutedReceiverIDs = channel.MutedUserIDs
+	blocks.excludeReceivers(&newMsg)
+	err = c.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		inserted, err := c.msgRepo.InsertMessage(ctx, newMsg)
+		if err != nil {
+			return err
+		}
+		err = c.voiceRecognitionPub.PublishMessage(ctx, inserted)
+		if err != nil {
+			return err
+		}
+		err = c.msgPubRepo.PublishMessage(ctx, inserted)
+		if err != nil {
+			return err
+		}
+		newMsg = inserted
+		return nil
+	})
 	if err != nil {
 		c.logger.Err(err)
+		// the voice file is not referenced by any message
+		if err := c.fileServiceClient.DeleteVoiceMessage(ctx, filename); err != nil {
+			c.logger.Error().Err(err).Msg("unable to delete voice message file")
+		}
 		return msg, custom_errors.ErrBroadcastingTextMessage
 	}
-	c.logger.Printf("new message saved: %+v\n", newMsg)
-	err = c.voiceRecognitionPub.PublishMessage(ctx, newMsg)
-	if err != nil {
-		c.logger.Err(err)
-		return msg, err
-	}
-	c.logger.Printf("new voice message published for recognition: %+v\n", newMsg)
+	c.logger.Printf("new voice message saved and published for recognition: %+v\n", newMsg)
 	return newMsg, nil
 }
 
@@ -353,72 +484,112 @@ func (c *ChatServiceImpl) updateTextMessage(ctx context.Context, msg chat_models
 	if err != nil {
 		return msg, err
 	}
+	if err = checkMessageInChannel(*oldMsg, channel); err != nil {
+		return msg, err
+	}
+	if err = checkChannelMember(channel, msg.UserID); err != nil {
+		return msg, err
+	}
+	if err = checkMessageAuthor(*oldMsg, msg.UserID); err != nil {
+		return msg, err
+	}
+	if oldMsg.IsDeleted() {
+		return msg, custom_errors.ErrMessageDeleted
+	}
 
 	newAttachmentsMap := make(map[string]any, len(msg.Attachments))
 	for _, attachment := range msg.Attachments {
-		newAttachmentsMap[attachment] = struct{}{}
+		newAttachmentsMap[attachment.Filename] = struct{}{}
 	}
-	attachmentsToDelete := make([]string, 0)
-	attachmentsToPreserve := make([]string, 0, len(oldMsg.Attachments))
+	// preserved attachments keep the metadata saved with the old message
+	attachmentsToPreserve := make([]chat_models.Attachment, 0, len(oldMsg.Attachments))
 	oldAttachmentsMap := make(map[string]any, len(oldMsg.Attachments))
 	for _, attachment := range oldMsg.Attachments {
-		oldAttachmentsMap[attachment] = struct{}{}
-		// если старого аттача нет в новых - удаляем
-		if _, ok := newAttachmentsMap[attachment]; !ok {
-			attachmentsToDelete = append(attachmentsToDelete, attachment)
-		} else {
+		oldAttachmentsMap[attachment.Filename] = struct{}{}
+		// removed attachments are not deleted from storage - they are still referenced by the revision
+		if _, ok := newAttachmentsMap[attachment.Filename]; ok {
 			attachmentsToPreserve = append(attachmentsToPreserve, attachment)
 		}
 	}
-	if len(attachmentsToDelete) > 0 {
-		err = c.fileServiceClient.DeleteAttachments(ctx, attachmentsToDelete)
-		if err != nil {
-			return chat_models.Message{}, err
-		}
-	}
 	attachmentsToCreate := make([]string, 0, len(oldMsg.Attachments))
 	for _, attachment := range msg.Attachments {
 		// если нового аттача нет в старых - создаем
-		if _, ok := oldAttachmentsMap[attachment]; !ok {
-			attachmentsToCreate = append(attachmentsToCreate, attachment)
+		if _, ok := oldAttachmentsMap[attachment.Filename]; !ok {
+			attachmentsToCreate = append(attachmentsToCreate, attachment.Filename)
 		}
 	}
-	var filenames []string
+	var createdAttachments []chat_models.Attachment
+	var prevMsgs []chat_models.Message
 	if len(attachmentsToCreate) > 0 {
-		filenames, err = c.fileServiceClient.MoveTempFilesToAttachments(ctx, attachmentsToCreate)
+		createdAttachments, err = c.fileServiceClient.MoveTempFilesToAttachments(ctx, attachmentsToCreate)
 		if err != nil {
 			return chat_models.Message{}, err
 		}
-		// publish potential materials for studymateriald to process
-		// if we failed - log and continue
-		prevMsgs, err := c.msgRepo.GetPreviousMessagesByMessageCreatedAt(ctx, channel.ID, oldMsg.CreatedAt, 10)
+		// previous messages are context for studymateriald, if we failed - log and continue without it
+		prevMsgs, err = c.msgRepo.GetPreviousMessagesByMessageCreatedAt(ctx, channel.ID, oldMsg.CreatedAt, 10)
 		if err != nil {
 			c.logger.Error().Err(err).Str("channel_id", channel.ID).Msg("unable to get previous messages in studymateriald sending")
-		} else {
-			err = c.publishAttachmentsToProcess(ctx, &msg, prevMsgs)
-			if err != nil {
-				c.logger.Error().Err(err).Any("msg", msg).Msg("unable to publish for studymateriald in update msg")
-			}
 		}
 	}
 
 	updatedAt := time.Now().Unix()
 	newMsg := chat_models.Message{
-		MessageID:   oldMsg.MessageID,
-		Event:       msg.Event,
-		Type:        msg.Type,
-		ChannelID:   channel.ID,
-		UserID:      oldMsg.UserID,
-		Payload:     msg.Payload,
-		CreatedAt:   oldMsg.CreatedAt,
-		UpdatedAt:   updatedAt,
-		Attachments: append(attachmentsToPreserve, filenames...),
-	}
-	err = c.msgRepo.UpdateMessage(ctx, newMsg)
+		MessageID:            oldMsg.MessageID,
+		Event:                msg.Event,
+		Type:                 msg.Type,
+		ChannelID:            channel.ID,
+		UserID:               oldMsg.UserID,
+		PeerID:               oldMsg.PeerID,
+		Payload:              msg.Payload,
+		Structurized:         oldMsg.Structurized,
+		StructurizedVersions: oldMsg.StructurizedVersions,
+		Voice:                oldMsg.Voice,
+		VoiceDuration:        oldMsg.VoiceDuration,
+		RecognizedVoice:      oldMsg.RecognizedVoice,
+		CreatedAt:            oldMsg.CreatedAt,
+		UpdatedAt:            updatedAt,
+		EditedAt:             updatedAt,
+		Attachments:          append(attachmentsToPreserve, createdAttachments...),
+		StudyMaterialID:      oldMsg.StudyMaterialID,
+		ForwardedFrom:        oldMsg.ForwardedFrom,
+		Reactions:            oldMsg.Reactions,
+		// reply target can't be changed on edit
+		ReplyToMessageID: oldMsg.ReplyToMessageID,
+	}
+	revision := chat_models.NewMessageRevision(*oldMsg, updatedAt)
+	c.attachQuote(ctx, &newMsg)
+	c.attachStudyMaterial(ctx, &newMsg)
+	newMsg.SetReceiverIDs(channel.UserIDs)
+	err = c.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		err := c.msgRepo.EditMessage(ctx, newMsg, revision)
+		if err != nil {
+			return err
+		}
+		// only new attachments are sent to studymateriald
+		if len(createdAttachments) > 0 {
+			err = c.publishAttachmentsToProcess(ctx, &chat_models.Message{
+				UserID:      newMsg.UserID,
+				Payload:     newMsg.Payload,
+				Attachments: createdAttachments,
+			}, prevMsgs)
+			if err != nil {
+				return err
+			}
+		}
+		return c.msgPubRepo.PublishMessage(ctx, newMsg)
+	})
 	if err != nil {
+		c.logger.Err(err)
+		c.deleteUnsavedAttachments(ctx, createdAttachments)
 		return msg, custom_errors.ErrBroadcastingTextMessage
 	}
-	newMsg.SetReceiverIDs(channel.UserIDs)
+	// the oldest revision is dropped above the limit, its files are not referenced anymore
+	droppedAttachments := chat_models.DroppedRevisionAttachments(*oldMsg, revision, newMsg.Attachments)
+	if len(droppedAttachments) > 0 {
+		if err = c.fileServiceClient.DeleteAttachments(ctx, droppedAttachments); err != nil {
+			c.logger.Error().Err(err).Str("message_id", newMsg.MessageID).Msg("unable to delete attachments of dropped revisions")
+		}
+	}
 	c.logger.Printf("message updated: %+v\n", newMsg)
 	return newMsg, nil
 }
@@ -438,13 +609,26 @@ func (c *ChatServiceImpl) deleteTextMessage(ctx context.Context, msg chat_models
 	if msg.ChannelID == "" {
 		return msg, custom_errors.ErrNoChannelID
 	}
-	channel, err = c.channelRepo.GetChannelByID(ctx, oldMsg.ChannelID)
+	channel, err = c.channelRepo.GetChannelByID(ctx, msg.ChannelID)
 	if err != nil {
 		return msg, err
 	}
+	if err = checkMessageInChannel(*oldMsg, channel); err != nil {
+		return msg, err
+	}
+	if err = checkChannelMember(channel, msg.UserID); err != nil {
+		return msg, err
+	}
+	if err = checkMessageAuthor(*oldMsg, msg.UserID); err != nil {
+		return msg, err
+	}
+	if oldMsg.IsDeleted() {
+		return msg, custom_errors.ErrMessageDeleted
+	}
 
-	if len(oldMsg.Attachments) > 0 {
-		err = c.fileServiceClient.DeleteAttachments(ctx, oldMsg.Attachments)
+	// revisions are cleared with the content, so their attachments go too
+	if attachments := oldMsg.AllAttachments(); len(attachments) > 0 {
+		err = c.fileServiceClient.DeleteAttachments(ctx, attachments)
 		if err != nil {
 			c.logger.Error().Err(err).Msg("unable to delete attachments")
 			err = nil
@@ -452,14 +636,13 @@ func (c *ChatServiceImpl) deleteTextMessage(ctx context.Context, msg chat_models
 		}
 	}
 
-	err = c.msgRepo.DeleteMessage(ctx, *oldMsg)
+	tombstone, err := c.deleteAndPublishMessage(ctx, *oldMsg, msg.Event, channel)
 	if err != nil {
-		return msg, custom_errors.ErrBroadcastingTextMessage
+		return msg, err
 	}
 
-	c.logger.Printf("message deleted: %+v\n", msg)
-	msg.SetReceiverIDs(channel.UserIDs)
-	return msg, nil
+	c.logger.Printf("message deleted: %+v\n", tombstone)
+	return tombstone, nil
 }
 
 func (c *ChatServiceImpl) deleteVoiceMessage(ctx context.Context, msg chat_models.Message) (chat_models.Message, error) {
@@ -475,37 +658,131 @@ func (c *ChatServiceImpl) deleteVoiceMessage(ctx context.Context, msg chat_model
 		return msg, err
 	}
 
-	if oldMsg.ChannelID == "" {
+	if msg.ChannelID == "" {
 		return msg, custom_errors.ErrNoChannelID
 	}
-	channel, err = c.channelRepo.GetChannelByID(ctx, oldMsg.ChannelID)
+	channel, err = c.channelRepo.GetChannelByID(ctx, msg.ChannelID)
 	if err != nil {
 		return msg, err
 	}
+	if err = checkMessageInChannel(*oldMsg, channel); err != nil {
+		return msg, err
+	}
+	if err = checkChannelMember(channel, msg.UserID); err != nil {
+		return msg, err
+	}
+	if err = checkMessageAuthor(*oldMsg, msg.UserID); err != nil {
+		return msg, err
+	}
+	if oldMsg.IsDeleted() {
+		return msg, custom_errors.ErrMessageDeleted
+	}
 
 	err = c.fileServiceClient.DeleteVoiceMessage(ctx, oldMsg.Voice)
 	if err != nil {
 		return msg, err
 	}
 
-	err = c.msgRepo.DeleteMessage(ctx, *oldMsg)
+	tombstone, err := c.deleteAndPublishMessage(ctx, *oldMsg, msg.Event, channel)
 	if err != nil {
-		return msg, custom_errors.ErrBroadcastingTextMessage
+		return msg, err
 	}
 
 	c.logger.Printf("message deleted: %+v\n", oldMsg)
-	msg.SetReceiverIDs(channel.UserIDs)
-	return msg, nil
+	return tombstone, nil
 }
 
-func (c *ChatServiceImpl) GetMessagesByChatID(ctx context.Context, chatID string, limit, offset int64) ([]chat_models.Message, error) {
-	if limit == 0 || limit > 1000 {
-		limit = 1000
+// deleteUnsavedAttachments removes files moved for a message that was not saved, they are not referenced by anyone
+func (c *ChatServiceImpl) deleteUnsavedAttachments(ctx context.Context, attachments []chat_models.Attachment) {
+	if len(attachments) == 0 {
+		return
+	}
+	if err := c.fileServiceClient.DeleteAttachments(ctx, chat_models.AttachmentFilenames(attachments)); err != nil {
+		c.logger.Error().Err(err).Msg("unable to delete unsaved attachments")
 	}
-	if offset < 0 {
-		offset = 0
+}
+
+// deleteAndPublishMessage replaces the message with a tombstone and publishes it in one transaction.
+// Pinned message is unpinned, so tombstones don't count towards the pins limit
+func (c *ChatServiceImpl) deleteAndPublishMessage(ctx context.Context, oldMsg chat_models.Message, event chat_models.MsgEvent, channel chat_models.Channel) (chat_models.Message, error) {
+	tombstone := chat_models.NewTombstone(oldMsg, time.Now().Unix())
+	tombstone.Event = event
+	tombstone.Type = chat_models.DeleteMessageType
+	tombstone.SetReceiverIDs(channel.UserIDs)
+	err := c.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		err := c.msgRepo.DeleteMessage(ctx, tombstone)
+		if err != nil {
+			return err
+		}
+		if slices.Contains(channel.PinnedMessageIDs, oldMsg.MessageID) {
+			err = c.unpinDeletedMessage(ctx, channel, tombstone)
+			if err != nil {
+				return err
+			}
+		}
+		return c.msgPubRepo.PublishMessage(ctx, tombstone)
+	})
+	if err != nil {
+		c.logger.Err(err)
+		return chat_models.Message{}, custom_errors.ErrBroadcastingTextMessage
 	}
-	return c.msgRepo.GetMessagesByChannelID(ctx, chatID, limit, offset)
+	return tombstone, nil
+}
+
+func (c *ChatServiceImpl) GetMessagesByChatID(ctx context.Context, userID, chatID, before, after string, limit int64) (*chat_models.MessagesPage, error) {
+	channel, err := c.channelRepo.GetChannelByID(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkChannelMember(channel, userID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxMessagesPageSize {
+		limit = maxMessagesPageSize
+	}
+	query := chat_models.MessagesQuery{
+		Limit: limit,
+	}
+	if before != "" && after != "" {
+		return nil, custom_errors.ErrBothCursorsProvided
+	}
+	if before != "" {
+		query.Before, err = chat_models.DecodeMessageCursor(before)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if after != "" {
+		query.After, err = chat_models.DecodeMessageCursor(after)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c.getMessagesPage(ctx, chatID, query)
+}
+
+// getMessagesPage loads messages and builds the cursor to continue in the same direction.
+// Cursor is empty when there is nothing more to load
+func (c *ChatServiceImpl) getMessagesPage(ctx context.Context, channelID string, query chat_models.MessagesQuery) (*chat_models.MessagesPage, error) {
+	msgs, err := c.msgRepo.GetMessagesByChannelID(ctx, channelID, query)
+	if err != nil {
+		return nil, err
+	}
+	c.attachQuotes(ctx, msgs)
+	c.attachStudyMaterials(ctx, msgs)
+	page := &chat_models.MessagesPage{
+		Messages: msgs,
+	}
+	if int64(len(msgs)) < query.Limit || len(msgs) == 0 {
+		return page, nil
+	}
+	// messages are sorted from the newest to the oldest
+	if query.After != nil {
+		page.NextCursor = chat_models.NewMessageCursor(msgs[0]).Encode()
+	} else {
+		page.NextCursor = chat_models.NewMessageCursor(msgs[len(msgs)-1]).Encode()
+	}
+	return page, nil
 }
 
 func (c *ChatServiceImpl) GetChannelsByUserID(ctx context.Context, userID string, limit, offset int64) ([]chat_models.Channel, error) {
@@ -515,7 +792,9 @@ func (c *ChatServiceImpl) GetChannelsByUserID(ctx context.Context, userID string
 	}
 
 	for i, channel := range channels {
-		msgs, err := c.msgRepo.GetMessagesByChannelID(ctx, channel.ID, 1, 0)
+		msgs, err := c.msgRepo.GetMessagesByChannelID(ctx, channel.ID, chat_models.MessagesQuery{
+			Limit: 1,
+		})
 		if err != nil {
 			c.logger.Error().Err(err).Msg(fmt.Sprintf("error getting messages by channel %s", channel.ID))
 			continue
@@ -524,23 +803,11 @@ func (c *ChatServiceImpl) GetChannelsByUserID(ctx context.Context, userID string
 			channels[i].LastMessage = &msgs[0]
 		}
 
-		for _, userID := range channel.UserIDs {
-			res, err := c.userService.GetUserByID(ctx, &pb.GetUserByIDRequest{
-				Id: userID,
-			})
-			if err != nil {
-				c.logger.Error().Err(err).Msg(fmt.Sprintf("unable to get user %s", userID))
-				continue
-			}
-			user, err := user_grpc.ConvertProtoToDomain(res.GetUser())
-			if err != nil {
-				c.logger.Error().Err(err).Msg(fmt.Sprintf("unable to convert to domain user %s", userID))
-				continue
-			}
-			user.Avatar = defaults.ApplyDefaultIfEmptyAvatar(user.Avatar)
-			channels[i].Users = append(channels[i].Users, *user)
-		}
+		channels[i].Muted = channels[i].IsMutedBy(userID)
+		c.attachUsers(ctx, &channels[i])
+		c.attachOnlineUsers(ctx, &channels[i])
 	}
+	c.attachReadStates(ctx, userID, channels)
 
 	return channels, nil
 }
@@ -557,37 +824,26 @@ func (c *ChatServiceImpl) concatenateMessages(messages []chat_models.Message) st
 	return res
 }
 
-func (c *ChatServiceImpl) trySendStructurizationRequest(ctx context.Context, question, answer string) (string, error) {
-	newCtx, cancel := context.WithTimeout(
-		ctx,
-		time.Duration(c.cfg.Services.StructurizationService.Timeout)*time.Second,
-	)
-	defer cancel()
-	i := c.cfg.Services.StructurizationService.MaxRetries
-loop:
-	for i > 0 {
-		select {
-		case <-newCtx.Done():
-			return "", custom_errors.ErrRequestTimeout
-		default:
-			i--
-			structurized, err := c.structurizationRepo.SendStructRequest(newCtx, question, answer)
-			if err != nil {
-				c.logger.Error().Err(err).Msg(fmt.Sprintf("trySendStructurizationRequest failed, %d retries remaining", i))
-				continue loop
-			}
-			return structurized.Explanation, nil
-		}
-	}
-
-	return "", custom_errors.ErrMaxRetriesExceeded
-}
-
-func (c *ChatServiceImpl) GetChannelByUserAndPeerIDs(ctx context.Context, userID, peerID string) (*chat_models.Channel, []chat_models.Message, error) {
+func (c *ChatServiceImpl) GetChannelByUserAndPeerIDs(ctx context.Context, userID, peerID string) (*chat_models.Channel, *chat_models.MessagesPage, error) {
 	channel, err := c.channelRepo.GetByUserIDs(ctx, []string{userID, peerID})
 	if err != nil {
 		return nil, nil, err
 	}
+	channel.Muted = channel.IsMutedBy(userID)
+	c.attachUsers(ctx, &channel)
+	c.attachOnlineUsers(ctx, &channel)
+	c.attachPinnedMessages(ctx, &channel)
+	page, err := c.getMessagesPage(ctx, channel.ID, chat_models.MessagesQuery{
+		Limit: channelPreviewMessagesLimit,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &channel, page, nil
+}
+
+// attachUsers fills channel.Users with members' profiles, skipping the ones that failed to load
+func (c *ChatServiceImpl) attachUsers(ctx context.Context, channel *chat_models.Channel) {
 	for _, userID := range channel.UserIDs {
 		res, err := c.userService.GetUserByID(ctx, &pb.GetUserByIDRequest{
 			Id: userID,
@@ -604,15 +860,35 @@ func (c *ChatServiceImpl) GetChannelByUserAndPeerIDs(ctx context.Context, userID
 		user.Avatar = defaults.ApplyDefaultIfEmptyAvatar(user.Avatar)
 		channel.Users = append(channel.Users, *user)
 	}
-	msgs, err := c.msgRepo.GetMessagesByChannelID(ctx, channel.ID, 200, 0)
+}
+
+func (c *ChatServiceImpl) GetMessageByID(ctx context.Context, userID, messageID string) (*chat_models.Message, error) {
+	msg, err := c.msgRepo.GetMessageByID(ctx, messageID)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	return &channel, msgs, nil
+	channel, err := c.channelRepo.GetChannelByID(ctx, msg.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkChannelMember(channel, userID); err != nil {
+		return nil, err
+	}
+	c.attachQuote(ctx, msg)
+	c.attachStudyMaterial(ctx, msg)
+	return msg, nil
 }
 
-func (c *ChatServiceImpl) GetMessageByID(ctx context.Context, messageID string) (*chat_models.Message, error) {
-	return c.msgRepo.GetMessageByID(ctx, messageID)
+// GetMessageRevisions returns previous versions of the message, the oldest first
+func (c *ChatServiceImpl) GetMessageRevisions(ctx context.Context, userID, messageID string) ([]chat_models.MessageRevision, error) {
+	msg, err := c.GetMessageByID(ctx, userID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if msg.Revisions == nil {
+		return []chat_models.MessageRevision{}, nil
+	}
+	return msg.Revisions, nil
 }
 
 func (c *ChatServiceImpl) publishAttachmentsToProcess(ctx context.Context, msg *chat_models.Message, prevMsgs []chat_models.Message) error {
@@ -622,13 +898,13 @@ func (c *ChatServiceImpl) publishAttachmentsToProcess(ctx context.Context, msg *
 	}
 	for _, attachment := range msg.Attachments {
 		err := c.studyMaterialPub.PublishAttachmentToParse(ctx, &study_material_models.AttachmentToParse{
-			Filename:         attachment,
+			Filename:         attachment.Filename,
 			AuthorID:         msg.UserID,
 			CurrMessageText:  msg.Payload,
 			PrevMessageTexts: prevMsgsTexts,
 		})
 		if err != nil {
-			c.logger.Error().Err(err).Msg(fmt.Sprintf("unable to publish attachment to process: %s", attachment))
+			c.logger.Error().Err(err).Msg(fmt.Sprintf("unable to publish attachment to process: %s", attachment.Filename))
 			return err
 		}
 	}
@@ -0,0 +1,141 @@
+package chat_service
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/config"
+	pb "github.com/Petr09Mitin/xrust-beze-back/proto/user"
+)
+
+// defaultStaticURL is the path nginx serves minio buckets under
+const defaultStaticURL = "/api/v1/static"
+
+// ExportChannel writes the whole channel history to w, the oldest message first.
+// Nothing is written to w until the user is checked to be a member, so errors before the first write are safe to report
+func (c *ChatServiceImpl) ExportChannel(ctx context.Context, userID, channelID string, format chat_models.ExportFormat, w io.Writer) error {
+	channel, err := c.channelRepo.GetChannelByID(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	if err = checkChannelMember(channel, userID); err != nil {
+		return err
+	}
+
+	c.attachUsers(ctx, &channel)
+	usernames := make(map[string]string, len(channel.Users))
+	for _, user := range channel.Users {
+		usernames[user.ID.Hex()] = user.Username
+	}
+	members := make([]chat_models.ExportedUser, 0, len(channel.UserIDs))
+	for _, memberID := range channel.UserIDs {
+		members = append(members, chat_models.ExportedUser{
+			UserID:   memberID,
+			Username: c.getExportUsername(ctx, usernames, memberID),
+		})
+	}
+
+	exporter := newChannelExporter(format, w)
+	err = exporter.writeChannel(chat_models.ExportedChannel{
+		ChannelID:  channel.ID,
+		Type:       channel.Type,
+		Title:      channel.Title,
+		Members:    members,
+		ExportedAt: time.Now().Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	studyMaterialNames := make(map[string]string)
+	err = c.msgRepo.ForEachChannelMessage(ctx, channel.ID, func(msg chat_models.Message) error {
+		return exporter.writeMessage(c.newExportedMessage(ctx, msg, usernames, studyMaterialNames))
+	})
+	if err != nil {
+		c.logger.Error().Err(err).Str("channel_id", channel.ID).Msg("unable to export channel history")
+		return err
+	}
+	return exporter.close()
+}
+
+func (c *ChatServiceImpl) newExportedMessage(ctx context.Context, msg chat_models.Message, usernames, studyMaterialNames map[string]string) chat_models.ExportedMessage {
+	exported := chat_models.ExportedMessage{
+		MessageID:        msg.MessageID,
+		Event:            msg.KindEvent(),
+		UserID:           msg.UserID,
+		Username:         c.getExportUsername(ctx, usernames, msg.UserID),
+		ReplyToMessageID: msg.ReplyToMessageID,
+		ForwardedFrom:    msg.ForwardedFrom,
+		Payload:          msg.Payload,
+		RecognizedVoice:  msg.RecognizedVoice,
+		Structurized:     msg.Structurized,
+		StudyMaterialID:  msg.StudyMaterialID,
+		Call:             msg.Call,
+		Edited:           len(msg.Revisions) > 0,
+		CreatedAt:        msg.CreatedAt,
+		UpdatedAt:        msg.UpdatedAt,
+	}
+	if msg.StudyMaterialID != "" {
+		exported.StudyMaterialName = c.getExportStudyMaterialName(ctx, studyMaterialNames, msg.StudyMaterialID)
+	}
+	if msg.Voice != "" {
+		exported.VoiceURL = c.getStaticFileURL(config.VoiceMessagesMinioBucket, msg.Voice)
+	}
+	for _, attachment := range msg.Attachments {
+		name := attachment.OriginalName
+		if name == "" {
+			name = attachment.Filename
+		}
+		exported.Attachments = append(exported.Attachments, chat_models.ExportedAttachment{
+			Name:     name,
+			URL:      c.getStaticFileURL(config.AttachmentsMinioBucket, attachment.Filename),
+			MimeType: attachment.MimeType,
+			Size:     attachment.Size,
+		})
+	}
+	return exported
+}
+
+// getExportUsername resolves authors who are not members anymore through the user service.
+// Unknown users are exported by id, the result is cached in usernames either way
+func (c *ChatServiceImpl) getExportUsername(ctx context.Context, usernames map[string]string, userID string) string {
+	if username, ok := usernames[userID]; ok {
+		return username
+	}
+	username := userID
+	res, err := c.userService.GetUserByID(ctx, &pb.GetUserByIDRequest{
+		Id: userID,
+	})
+	if err != nil {
+		c.logger.Error().Err(err).Str("user_id", userID).Msg("unable to get user for export")
+	} else if res.GetUser().GetUsername() != "" {
+		username = res.GetUser().GetUsername()
+	}
+	usernames[userID] = username
+	return username
+}
+
+// getExportStudyMaterialName resolves the name of the shared study material.
+// Deleted materials have no name, the result is cached in studyMaterialNames either way
+func (c *ChatServiceImpl) getExportStudyMaterialName(ctx context.Context, studyMaterialNames map[string]string, studyMaterialID string) string {
+	if name, ok := studyMaterialNames[studyMaterialID]; ok {
+		return name
+	}
+	name := ""
+	card, err := c.getStudyMaterialCard(ctx, studyMaterialID)
+	if err == nil {
+		name = card.Name
+	}
+	studyMaterialNames[studyMaterialID] = name
+	return name
+}
+
+func (c *ChatServiceImpl) getStaticFileURL(bucket, filename string) string {
+	staticURL := defaultStaticURL
+	if c.cfg.Export != nil && c.cfg.Export.StaticURL != "" {
+		staticURL = strings.TrimSuffix(c.cfg.Export.StaticURL, "/")
+	}
+	return staticURL + "/" + bucket + "/" + filename
+}
@@ -0,0 +1,229 @@
+package chat_service
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+)
+
+const exportTimeLayout = "2006-01-02 15:04 MST"
+
+// channelExporter renders the export document piece by piece, so it can be streamed
+type channelExporter interface {
+	writeChannel(channel chat_models.ExportedChannel) error
+	writeMessage(msg chat_models.ExportedMessage) error
+	// close finishes the document and flushes the buffered output
+	close() error
+}
+
+func newChannelExporter(format chat_models.ExportFormat, w io.Writer) channelExporter {
+	buf := bufio.NewWriter(w)
+	switch format {
+	case chat_models.JSONExportFormat:
+		return &jsonExporter{w: buf}
+	case chat_models.HTMLExportFormat:
+		return &htmlExporter{w: buf}
+	default:
+		return &markdownExporter{w: buf}
+	}
+}
+
+func formatExportTime(unix int64) string {
+	return time.Unix(unix, 0).UTC().Format(exportTimeLayout)
+}
+
+func getExportTitle(channel chat_models.ExportedChannel) string {
+	if channel.Title != "" {
+		return channel.Title
+	}
+	usernames := make([]string, 0, len(channel.Members))
+	for _, member := range channel.Members {
+		usernames = append(usernames, member.Username)
+	}
+	return "Chat: " + strings.Join(usernames, ", ")
+}
+
+func getExportMembers(channel chat_models.ExportedChannel) string {
+	usernames := make([]string, 0, len(channel.Members))
+	for _, member := range channel.Members {
+		usernames = append(usernames, member.Username)
+	}
+	return strings.Join(usernames, ", ")
+}
+
+func formatExportCall(call *chat_models.CallInfo) string {
+	res := fmt.Sprintf("%s call %s", call.Media, call.Status)
+	if call.Duration > 0 {
+		res += fmt.Sprintf(", %s", time.Duration(call.Duration)*time.Second)
+	}
+	return res
+}
+
+// jsonExporter writes {"channel": {...}, "messages": [...]}
+type jsonExporter struct {
+	w        *bufio.Writer
+	messages int
+}
+
+func (e *jsonExporter) writeChannel(channel chat_models.ExportedChannel) error {
+	data, err := json.Marshal(channel)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(e.w, `{"channel":%s,"messages":[`, data)
+	return err
+}
+
+func (e *jsonExporter) writeMessage(msg chat_models.ExportedMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if e.messages > 0 {
+		if err = e.w.WriteByte(','); err != nil {
+			return err
+		}
+	}
+	e.messages++
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonExporter) close() error {
+	if _, err := e.w.WriteString("]}\n"); err != nil {
+		return err
+	}
+	return e.w.Flush()
+}
+
+// markdownExporter keeps message texts as is, they are already written in markdown
+type markdownExporter struct {
+	w *bufio.Writer
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+)
+
+func (e *markdownExporter) writeChannel(channel chat_models.ExportedChannel) error {
+	_, err := fmt.Fprintf(e.w, "# %s\n\nExported: %s\n\nMembers: %s\n",
+		markdownEscaper.Replace(getExportTitle(channel)),
+		formatExportTime(channel.ExportedAt),
+		markdownEscaper.Replace(getExportMembers(channel)),
+	)
+	return err
+}
+
+func (e *markdownExporter) writeMessage(msg chat_models.ExportedMessage) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n---\n\n**%s** · %s", markdownEscaper.Replace(msg.Username), formatExportTime(msg.CreatedAt))
+	if msg.Edited {
+		b.WriteString(" (edited)")
+	}
+	b.WriteString("\n\n")
+	if msg.ForwardedFrom != nil {
+		b.WriteString("_Forwarded message_\n\n")
+	}
+	if msg.Payload != "" {
+		b.WriteString(msg.Payload + "\n\n")
+	}
+	if msg.VoiceURL != "" {
+		fmt.Fprintf(&b, "[Voice message](%s)\n\n", msg.VoiceURL)
+	}
+	if msg.RecognizedVoice != "" {
+		b.WriteString("> " + strings.ReplaceAll(msg.RecognizedVoice, "\n", "\n> ") + "\n\n")
+	}
+	for _, attachment := range msg.Attachments {
+		fmt.Fprintf(&b, "- [%s](%s)\n", markdownEscaper.Replace(attachment.Name), attachment.URL)
+	}
+	if len(msg.Attachments) > 0 {
+		b.WriteString("\n")
+	}
+	if msg.StudyMaterialID != "" {
+		fmt.Fprintf(&b, "Study material: %s\n\n", markdownEscaper.Replace(msg.StudyMaterialTitle()))
+	}
+	if msg.Call != nil {
+		b.WriteString(formatExportCall(msg.Call) + "\n\n")
+	}
+	if msg.Structurized != "" {
+		b.WriteString("**AI explanation**\n\n" + msg.Structurized + "\n\n")
+	}
+	_, err := e.w.WriteString(b.String())
+	return err
+}
+
+func (e *markdownExporter) close() error {
+	return e.w.Flush()
+}
+
+// htmlExporter writes a standalone page, texts are escaped and shown with their line breaks
+type htmlExporter struct {
+	w *bufio.Writer
+}
+
+var exportHTMLTemplates = template.Must(template.New("export").Funcs(template.FuncMap{
+	"time":     formatExportTime,
+	"title":    getExportTitle,
+	"members":  getExportMembers,
+	"callinfo": formatExportCall,
+}).Parse(`
+{{define "channel"}}<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{title .}}</title>
+<style>
+body { font-family: sans-serif; max-width: 860px; margin: 0 auto; padding: 16px; }
+.message { border-top: 1px solid #ddd; padding: 8px 0; }
+.meta { color: #666; font-size: 0.9em; }
+.text, .structurized { white-space: pre-wrap; }
+.voice { font-style: italic; border-left: 3px solid #ccc; padding-left: 8px; }
+.structurized { background: #f5f5f5; padding: 8px; }
+</style>
+</head>
+<body>
+<h1>{{title .}}</h1>
+<p class="meta">Exported: {{time .ExportedAt}}<br>Members: {{members .}}</p>
+{{end}}
+{{define "message"}}<div class="message" id="{{.MessageID}}">
+<div class="meta"><b>{{.Username}}</b> · {{time .CreatedAt}}{{if .Edited}} (edited){{end}}</div>
+{{if .ForwardedFrom}}<div class="meta">Forwarded message</div>
+{{end}}{{if .Payload}}<div class="text">{{.Payload}}</div>
+{{end}}{{if .VoiceURL}}<div><a href="{{.VoiceURL}}">Voice message</a></div>
+{{end}}{{if .RecognizedVoice}}<div class="voice text">{{.RecognizedVoice}}</div>
+{{end}}{{if .Attachments}}<ul>{{range .Attachments}}<li><a href="{{.URL}}">{{.Name}}</a></li>{{end}}</ul>
+{{end}}{{if .StudyMaterialID}}<div>Study material: {{.StudyMaterialTitle}}</div>
+{{end}}{{if .Call}}<div>{{callinfo .Call}}</div>
+{{end}}{{if .Structurized}}<div class="structurized"><b>AI explanation</b>
+{{.Structurized}}</div>
+{{end}}</div>
+{{end}}
+{{define "end"}}</body>
+</html>
+{{end}}`))
+
+func (e *htmlExporter) writeChannel(channel chat_models.ExportedChannel) error {
+	return exportHTMLTemplates.ExecuteTemplate(e.w, "channel", channel)
+}
+
+func (e *htmlExporter) writeMessage(msg chat_models.ExportedMessage) error {
+	return exportHTMLTemplates.ExecuteTemplate(e.w, "message", msg)
+}
+
+func (e *htmlExporter) close() error {
+	if err := exportHTMLTemplates.ExecuteTemplate(e.w, "end", nil); err != nil {
+		return err
+	}
+	return e.w.Flush()
+}
@@ -0,0 +1,74 @@
+package chat_service
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+	channelrepo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/channel"
+	message_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/chat"
+)
+
+// fakeMsgRepo implements only the methods used by the tested code, the rest panic on the nil interface
+type fakeMsgRepo struct {
+	message_repo.MessageRepo
+	msgs    []chat_models.Message
+	queries []chat_models.MessagesQuery
+	// changedSince is the last request of GetMessagesChangedSince
+	changedSince struct {
+		channelIDs   []string
+		since, limit int64
+	}
+}
+
+func (r *fakeMsgRepo) GetMessageByID(_ context.Context, id string) (*chat_models.Message, error) {
+	for _, msg := range r.msgs {
+		if msg.MessageID == id {
+			return &msg, nil
+		}
+	}
+	return nil, custom_errors.ErrNotFound
+}
+
+func (r *fakeMsgRepo) GetMessagesChangedSince(_ context.Context, channelIDs []string, since, limit int64) ([]chat_models.Message, error) {
+	r.changedSince.channelIDs = channelIDs
+	r.changedSince.since = since
+	r.changedSince.limit = limit
+	if int64(len(r.msgs)) > limit {
+		return slices.Clone(r.msgs[:limit]), nil
+	}
+	return slices.Clone(r.msgs), nil
+}
+
+func (r *fakeMsgRepo) GetMessagesByChannelID(_ context.Context, _ string, query chat_models.MessagesQuery) ([]chat_models.Message, error) {
+	r.queries = append(r.queries, query)
+	return r.msgs, nil
+}
+
+type fakeChannelRepo struct {
+	channelrepo.ChannelRepository
+	channels map[string]chat_models.Channel
+}
+
+func (r *fakeChannelRepo) GetChannelsByUserID(_ context.Context, userID string, _, _ int64) ([]chat_models.Channel, error) {
+	res := make([]chat_models.Channel, 0)
+	for _, channel := range r.channels {
+		if slices.Contains(channel.UserIDs, userID) {
+			res = append(res, channel)
+		}
+	}
+	slices.SortFunc(res, func(a, b chat_models.Channel) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return res, nil
+}
+
+func (r *fakeChannelRepo) GetChannelByID(_ context.Context, id string) (chat_models.Channel, error) {
+	channel, ok := r.channels[id]
+	if !ok {
+		return chat_models.Channel{}, custom_errors.ErrNotFound
+	}
+	return channel, nil
+}
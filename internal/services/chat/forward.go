@@ -0,0 +1,112 @@
+package chat_service
+
+import (
+	"context"
+	"time"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+)
+
+// ProcessForwardMessage copies the message with message_id into the channel with channel_id.
+// Attachments are copied by the file service, so the copy does not depend on the original.
+// Returns id of the new message
+func (c *ChatServiceImpl) ProcessForwardMessage(ctx context.Context, msg chat_models.Message) (string, error) {
+	if msg.MessageID == "" {
+		return "", custom_errors.ErrNoMessageID
+	}
+	if msg.ChannelID == "" {
+		return "", custom_errors.ErrNoChannelID
+	}
+	source, err := c.msgRepo.GetMessageByID(ctx, msg.MessageID)
+	if err != nil {
+		return "", err
+	}
+	sourceChannel, err := c.channelRepo.GetChannelByID(ctx, source.ChannelID)
+	if err != nil {
+		return "", err
+	}
+	if err = checkChannelMember(sourceChannel, msg.UserID); err != nil {
+		return "", err
+	}
+	if source.IsDeleted() {
+		return "", custom_errors.ErrMessageDeleted
+	}
+	if !source.IsForwardable() {
+		return "", custom_errors.ErrMessageCannotBeForwarded
+	}
+
+	blocks := c.getBlockRelations(ctx, msg.UserID)
+	channel, err := c.channelRepo.GetChannelByID(ctx, msg.ChannelID)
+	if err != nil {
+		return "", err
+	}
+	if err = checkChannelMember(channel, msg.UserID); err != nil {
+		return "", err
+	}
+	if err = blocks.checkChannel(channel); err != nil {
+		return "", err
+	}
+
+	attachments, err := c.copyAttachments(ctx, source.Attachments)
+	if err != nil {
+		return "", err
+	}
+	createdAt := time.Now().Unix()
+	newMsg := chat_models.Message{
+		Event:           source.KindEvent(),
+		Type:            chat_models.SendMessageType,
+		ChannelID:       channel.ID,
+		UserID:          msg.UserID,
+		Payload:         source.Payload,
+		Structurized:    source.Structurized,
+		Attachments:     attachments,
+		StudyMaterialID: source.StudyMaterialID,
+		ForwardedFrom:   chat_models.NewForwardedFrom(*source),
+		CreatedAt:       createdAt,
+		UpdatedAt:       createdAt,
+	}
+	c.attachStudyMaterial(ctx, &newMsg)
+	newMsg.SetReceiverIDs(channel.UserIDs)
+	newMsg.MutedReceiverIDs = channel.MutedUserIDs
+	blocks.excludeReceivers(&newMsg)
+	err = c.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		inserted, err := c.msgRepo.InsertMessage(ctx, newMsg)
+		if err != nil {
+			return err
+		}
+		err = c.msgPubRepo.PublishMessage(ctx, inserted)
+		if err != nil {
+			return err
+		}
+		newMsg = inserted
+		return nil
+	})
+	if err != nil {
+		c.logger.Error().Err(err).Str("message_id", source.MessageID).Str("channel_id", channel.ID).Msg("unable to save forwarded message")
+		c.deleteUnsavedAttachments(ctx, attachments)
+		return "", custom_errors.ErrBroadcastingTextMessage
+	}
+	return newMsg.MessageID, nil
+}
+
+// copyAttachments copies files of the attachments, metadata is the same for the copies
+func (c *ChatServiceImpl) copyAttachments(ctx context.Context, attachments []chat_models.Attachment) ([]chat_models.Attachment, error) {
+	copies := make([]chat_models.Attachment, 0, len(attachments))
+	if len(attachments) == 0 {
+		return copies, nil
+	}
+	filenames, err := c.fileServiceClient.CopyAttachments(ctx, chat_models.AttachmentFilenames(attachments))
+	if err != nil {
+		c.logger.Error().Err(err).Msg("unable to copy attachments")
+		return nil, err
+	}
+	if len(filenames) != len(attachments) {
+		return nil, custom_errors.ErrInternal
+	}
+	for i, attachment := range attachments {
+		attachment.Filename = filenames[i]
+		copies = append(copies, attachment)
+	}
+	return copies, nil
+}
@@ -0,0 +1,276 @@
+package chat_service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+	pb "github.com/Petr09Mitin/xrust-beze-back/proto/user"
+)
+
+const (
+	maxGroupChannelMembers  = 100
+	maxGroupChannelTitleLen = 100
+)
+
+func (c *ChatServiceImpl) CreateGroupChannel(ctx context.Context, ownerID string, req chat_models.GroupChannelRequest) (*chat_models.Channel, error) {
+	title, err := validateGroupTitle(req.Title)
+	if err != nil {
+		return nil, err
+	}
+	memberIDs := c.normalizeMemberIDs(req.UserIDs, ownerID)
+	if len(memberIDs) == 0 {
+		return nil, custom_errors.ErrNoGroupMembers
+	}
+	if len(memberIDs)+1 > maxGroupChannelMembers {
+		return nil, custom_errors.ErrGroupMembersLimitExceeded
+	}
+	if err = c.checkUsersExist(ctx, memberIDs); err != nil {
+		return nil, err
+	}
+	if err = c.checkNotBlocked(ctx, ownerID, memberIDs); err != nil {
+		return nil, err
+	}
+
+	created := time.Now().Unix()
+	channel, err := c.channelRepo.InsertChannel(ctx, chat_models.Channel{
+		Type:     chat_models.GroupChannelType,
+		Title:    title,
+		OwnerID:  ownerID,
+		AdminIDs: []string{ownerID},
+		UserIDs:  append([]string{ownerID}, memberIDs...),
+		Created:  created,
+		Updated:  created,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.publishMembersEvent(ctx, chat_models.MemberJoinedEvent, channel, ownerID, channel.UserIDs, nil)
+	c.attachUsers(ctx, &channel)
+	return &channel, nil
+}
+
+func (c *ChatServiceImpl) UpdateGroupChannel(ctx context.Context, userID, channelID string, req chat_models.GroupChannelRequest) (*chat_models.Channel, error) {
+	title, err := validateGroupTitle(req.Title)
+	if err != nil {
+		return nil, err
+	}
+	channel, err := c.getGroupChannelForAdmin(ctx, userID, channelID)
+	if err != nil {
+		return nil, err
+	}
+	channel.Title = title
+	channel.Updated = time.Now().Unix()
+	if err = c.channelRepo.UpdateChannel(ctx, channel); err != nil {
+		return nil, err
+	}
+
+	c.attachUsers(ctx, &channel)
+	return &channel, nil
+}
+
+func (c *ChatServiceImpl) AddGroupMembers(ctx context.Context, userID, channelID string, memberIDs []string) (*chat_models.Channel, error) {
+	channel, err := c.getGroupChannelForAdmin(ctx, userID, channelID)
+	if err != nil {
+		return nil, err
+	}
+	newMemberIDs := make([]string, 0, len(memberIDs))
+	for _, memberID := range c.normalizeMemberIDs(memberIDs, userID) {
+		if !slices.Contains(channel.UserIDs, memberID) {
+			newMemberIDs = append(newMemberIDs, memberID)
+		}
+	}
+	if len(newMemberIDs) == 0 {
+		return nil, custom_errors.ErrNoGroupMembers
+	}
+	if len(channel.UserIDs)+len(newMemberIDs) > maxGroupChannelMembers {
+		return nil, custom_errors.ErrGroupMembersLimitExceeded
+	}
+	if err = c.checkUsersExist(ctx, newMemberIDs); err != nil {
+		return nil, err
+	}
+	if err = c.checkNotBlocked(ctx, userID, newMemberIDs); err != nil {
+		return nil, err
+	}
+
+	channel, err = c.channelRepo.AddMembers(ctx, channel.ID, newMemberIDs, maxGroupChannelMembers, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	c.publishMembersEvent(ctx, chat_models.MemberJoinedEvent, channel, userID, newMemberIDs, nil)
+	c.attachUsers(ctx, &channel)
+	return &channel, nil
+}
+
+func (c *ChatServiceImpl) RemoveGroupMember(ctx context.Context, userID, channelID, memberID string) (*chat_models.Channel, error) {
+	channel, err := c.getGroupChannelForAdmin(ctx, userID, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkChannelMember(channel, memberID); err != nil {
+		return nil, err
+	}
+	if memberID == channel.OwnerID {
+		return nil, custom_errors.ErrCannotRemoveChannelOwner
+	}
+	// only owner is allowed to remove other admins
+	if channel.IsAdmin(memberID) && userID != channel.OwnerID {
+		return nil, custom_errors.ErrNotChannelAdmin
+	}
+
+	channel, err = c.channelRepo.RemoveMember(ctx, channel.ID, memberID, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	c.publishMembersEvent(ctx, chat_models.MemberLeftEvent, channel, userID, []string{memberID}, []string{memberID})
+	c.attachUsers(ctx, &channel)
+	return &channel, nil
+}
+
+func (c *ChatServiceImpl) LeaveGroupChannel(ctx context.Context, userID, channelID string) error {
+	channel, err := c.channelRepo.GetChannelByID(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	if !channel.IsGroup() {
+		return custom_errors.ErrNotGroupChannel
+	}
+	if err = checkChannelMember(channel, userID); err != nil {
+		return err
+	}
+
+	updated := time.Now().Unix()
+	channel, err = c.channelRepo.RemoveMember(ctx, channel.ID, userID, updated)
+	if err != nil {
+		return err
+	}
+	// pass ownership to the next admin or, if there are none, to the oldest member
+	if channel.OwnerID == userID && len(channel.UserIDs) > 0 {
+		channel.OwnerID = channel.UserIDs[0]
+		if len(channel.AdminIDs) > 0 {
+			channel.OwnerID = channel.AdminIDs[0]
+		} else {
+			channel.AdminIDs = []string{channel.OwnerID}
+		}
+		channel.Updated = updated
+		if err = c.channelRepo.UpdateChannel(ctx, channel); err != nil {
+			return err
+		}
+	}
+
+	c.publishMembersEvent(ctx, chat_models.MemberLeftEvent, channel, userID, []string{userID}, []string{userID})
+	return nil
+}
+
+func (c *ChatServiceImpl) SetGroupAdmin(ctx context.Context, userID, channelID, adminID string, isAdmin bool) (*chat_models.Channel, error) {
+	channel, err := c.channelRepo.GetChannelByID(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if !channel.IsGroup() {
+		return nil, custom_errors.ErrNotGroupChannel
+	}
+	if channel.OwnerID != userID {
+		return nil, custom_errors.ErrNotChannelAdmin
+	}
+	if err = checkChannelMember(channel, adminID); err != nil {
+		return nil, err
+	}
+	if adminID == channel.OwnerID {
+		return nil, custom_errors.ErrCannotRemoveChannelOwner
+	}
+
+	adminIDs := slices.DeleteFunc(slices.Clone(channel.AdminIDs), func(id string) bool {
+		return id == adminID
+	})
+	if isAdmin {
+		adminIDs = append(adminIDs, adminID)
+	}
+	channel.AdminIDs = adminIDs
+	channel.Updated = time.Now().Unix()
+	if err = c.channelRepo.UpdateChannel(ctx, channel); err != nil {
+		return nil, err
+	}
+
+	c.attachUsers(ctx, &channel)
+	return &channel, nil
+}
+
+func (c *ChatServiceImpl) getGroupChannelForAdmin(ctx context.Context, userID, channelID string) (chat_models.Channel, error) {
+	channel, err := c.channelRepo.GetChannelByID(ctx, channelID)
+	if err != nil {
+		return chat_models.Channel{}, err
+	}
+	if !channel.IsGroup() {
+		return chat_models.Channel{}, custom_errors.ErrNotGroupChannel
+	}
+	if err = checkChannelMember(channel, userID); err != nil {
+		return chat_models.Channel{}, err
+	}
+	if !channel.IsAdmin(userID) {
+		return chat_models.Channel{}, custom_errors.ErrNotChannelAdmin
+	}
+	return channel, nil
+}
+
+// normalizeMemberIDs trims, deduplicates and drops the acting user from the list
+func (c *ChatServiceImpl) normalizeMemberIDs(memberIDs []string, actorID string) []string {
+	res := make([]string, 0, len(memberIDs))
+	for _, memberID := range memberIDs {
+		memberID = strings.TrimSpace(memberID)
+		if memberID == "" || memberID == actorID || slices.Contains(res, memberID) {
+			continue
+		}
+		res = append(res, memberID)
+	}
+	return res
+}
+
+func (c *ChatServiceImpl) checkUsersExist(ctx context.Context, userIDs []string) error {
+	for _, userID := range userIDs {
+		_, err := c.userService.GetUserByID(ctx, &pb.GetUserByIDRequest{
+			Id: userID,
+		})
+		if err != nil {
+			c.logger.Error().Err(err).Msg(fmt.Sprintf("unable to get user %s", userID))
+			return fmt.Errorf("%w: %s", custom_errors.ErrInvalidUserID, userID)
+		}
+	}
+	return nil
+}
+
+// publishMembersEvent notifies current members (and extraReceivers, e.g. removed user) about membership changes.
+// Membership is already persisted, so we only log on failure
+func (c *ChatServiceImpl) publishMembersEvent(ctx context.Context, event chat_models.MsgEvent, channel chat_models.Channel, actorID string, memberIDs []string, extraReceivers []string) {
+	msg := chat_models.Message{
+		Event:     event,
+		Type:      chat_models.SendMessageType,
+		ChannelID: channel.ID,
+		UserID:    actorID,
+		MemberIDs: memberIDs,
+		CreatedAt: channel.Updated,
+	}
+	msg.SetReceiverIDs(append(slices.Clone(channel.UserIDs), extraReceivers...))
+	if err := c.msgPubRepo.PublishMessage(ctx, msg); err != nil {
+		c.logger.Error().Err(err).Str("channel_id", channel.ID).Msg(fmt.Sprintf("unable to publish %s", event))
+	}
+}
+
+func validateGroupTitle(title string) (string, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return "", custom_errors.ErrNoGroupTitle
+	}
+	if utf8.RuneCountInString(title) > maxGroupChannelTitleLen {
+		return "", custom_errors.ErrGroupTitleTooLong
+	}
+	return title, nil
+}
@@ -0,0 +1,141 @@
+package chat_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+	"github.com/rs/zerolog"
+)
+
+func TestGetMessagesByChatIDCursors(t *testing.T) {
+	// the repo returns messages from the newest to the oldest
+	msgs := []chat_models.Message{
+		{MessageID: "m3", CreatedAt: 30},
+		{MessageID: "m2", CreatedAt: 20},
+		{MessageID: "m1", CreatedAt: 20},
+	}
+	newest := chat_models.NewMessageCursor(msgs[0])
+	oldest := chat_models.NewMessageCursor(msgs[2])
+	cursor := &chat_models.MessageCursor{CreatedAt: 25, MessageID: "m"}
+
+	tests := []struct {
+		name           string
+		before         string
+		after          string
+		limit          int64
+		wantQuery      chat_models.MessagesQuery
+		wantNextCursor string
+		wantErr        error
+	}{
+		{
+			name:           "newest page continues from its oldest message",
+			limit:          3,
+			wantQuery:      chat_models.MessagesQuery{Limit: 3},
+			wantNextCursor: oldest.Encode(),
+		},
+		{
+			name:           "before cursor continues from the oldest message",
+			before:         cursor.Encode(),
+			limit:          3,
+			wantQuery:      chat_models.MessagesQuery{Before: cursor, Limit: 3},
+			wantNextCursor: oldest.Encode(),
+		},
+		{
+			name:           "after cursor continues from the newest message",
+			after:          cursor.Encode(),
+			limit:          3,
+			wantQuery:      chat_models.MessagesQuery{After: cursor, Limit: 3},
+			wantNextCursor: newest.Encode(),
+		},
+		{
+			name:      "short page has no next cursor",
+			limit:     10,
+			wantQuery: chat_models.MessagesQuery{Limit: 10},
+		},
+		{
+			name:      "too large limit is capped",
+			limit:     maxMessagesPageSize + 1,
+			wantQuery: chat_models.MessagesQuery{Limit: maxMessagesPageSize},
+		},
+		{
+			name:    "both cursors",
+			before:  cursor.Encode(),
+			after:   cursor.Encode(),
+			limit:   3,
+			wantErr: custom_errors.ErrBothCursorsProvided,
+		},
+		{
+			name:    "invalid cursor",
+			before:  "not a cursor",
+			limit:   3,
+			wantErr: custom_errors.ErrInvalidCursor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgRepo := &fakeMsgRepo{msgs: msgs}
+			c := &ChatServiceImpl{
+				msgRepo: msgRepo,
+				channelRepo: &fakeChannelRepo{channels: map[string]chat_models.Channel{
+					"channel": {ID: "channel", UserIDs: []string{"alice", "bob"}},
+				}},
+				logger: zerolog.Nop(),
+			}
+			page, err := c.GetMessagesByChatID(context.Background(), "alice", "channel", tt.before, tt.after, tt.limit)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got %v, want %v", err, tt.wantErr)
+				}
+				if len(msgRepo.queries) != 0 {
+					t.Fatalf("repo is queried with %+v", msgRepo.queries)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("get messages: %v", err)
+			}
+			if len(msgRepo.queries) != 1 {
+				t.Fatalf("got %d queries, want 1", len(msgRepo.queries))
+			}
+			assertMessagesQuery(t, msgRepo.queries[0], tt.wantQuery)
+			if page.NextCursor != tt.wantNextCursor {
+				t.Fatalf("got next cursor %q, want %q", page.NextCursor, tt.wantNextCursor)
+			}
+		})
+	}
+}
+
+func TestGetMessagesByChatIDRejectsNotMember(t *testing.T) {
+	c := &ChatServiceImpl{
+		msgRepo: &fakeMsgRepo{},
+		channelRepo: &fakeChannelRepo{channels: map[string]chat_models.Channel{
+			"channel": {ID: "channel", UserIDs: []string{"alice", "bob"}},
+		}},
+		logger: zerolog.Nop(),
+	}
+	_, err := c.GetMessagesByChatID(context.Background(), "stranger", "channel", "", "", 10)
+	if !errors.Is(err, custom_errors.ErrNotChannelMember) {
+		t.Fatalf("got %v, want ErrNotChannelMember", err)
+	}
+}
+
+func assertMessagesQuery(t *testing.T, got, want chat_models.MessagesQuery) {
+	t.Helper()
+	if got.Limit != want.Limit {
+		t.Fatalf("got limit %d, want %d", got.Limit, want.Limit)
+	}
+	for _, c := range []struct {
+		name      string
+		got, want *chat_models.MessageCursor
+	}{
+		{name: "before", got: got.Before, want: want.Before},
+		{name: "after", got: got.After, want: want.After},
+	} {
+		if (c.got == nil) != (c.want == nil) || (c.got != nil && *c.got != *c.want) {
+			t.Fatalf("got %s cursor %+v, want %+v", c.name, c.got, c.want)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package chat_service
+
+import (
+	"context"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+)
+
+// SetChannelMuted mutes or unmutes the channel for the user.
+// Muted channel still delivers messages, they are only marked as silent for the user
+func (c *ChatServiceImpl) SetChannelMuted(ctx context.Context, userID, channelID string, muted bool) (*chat_models.Channel, error) {
+	channel, err := c.channelRepo.GetChannelByID(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkChannelMember(channel, userID); err != nil {
+		return nil, err
+	}
+	if muted {
+		channel, err = c.channelRepo.MuteChannel(ctx, channel.ID, userID)
+	} else {
+		channel, err = c.channelRepo.UnmuteChannel(ctx, channel.ID, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	channel.Muted = channel.IsMutedBy(userID)
+	c.attachUsers(ctx, &channel)
+	return &channel, nil
+}
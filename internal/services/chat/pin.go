@@ -0,0 +1,98 @@
+package chat_service
+
+import (
+	"context"
+	"slices"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+)
+
+// ProcessPinEvent pins (send_message) or unpins (delete_message) the message in its channel.
+// Result is broadcast with the full list of pinned message ids
+func (c *ChatServiceImpl) ProcessPinEvent(ctx context.Context, msg chat_models.Message) error {
+	if msg.MessageID == "" {
+		return custom_errors.ErrNoMessageID
+	}
+	oldMsg, err := c.msgRepo.GetMessageByID(ctx, msg.MessageID)
+	if err != nil {
+		return err
+	}
+	channel, err := c.channelRepo.GetChannelByID(ctx, oldMsg.ChannelID)
+	if err != nil {
+		return err
+	}
+	if err = checkChannelMember(channel, msg.UserID); err != nil {
+		return err
+	}
+
+	return c.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		var newChannel chat_models.Channel
+		var err error
+		switch msg.Type {
+		case chat_models.SendMessageType:
+			if oldMsg.IsDeleted() {
+				return custom_errors.ErrMessageDeleted
+			}
+			newChannel, err = c.channelRepo.PinMessage(ctx, channel.ID, oldMsg.MessageID, chat_models.MaxPinnedMessages)
+		case chat_models.DeleteMessageType:
+			// deleted messages can still be unpinned
+			newChannel, err = c.channelRepo.UnpinMessage(ctx, channel.ID, oldMsg.MessageID)
+		default:
+			return custom_errors.ErrInvalidMessageType
+		}
+		if err != nil {
+			return err
+		}
+
+		pinEvent := chat_models.Message{
+			MessageID:        oldMsg.MessageID,
+			Event:            chat_models.PinEvent,
+			Type:             msg.Type,
+			ChannelID:        channel.ID,
+			UserID:           msg.UserID,
+			PinnedMessageIDs: newChannel.PinnedMessageIDs,
+		}
+		pinEvent.SetReceiverIDs(channel.UserIDs)
+		return c.msgPubRepo.PublishMessage(ctx, pinEvent)
+	})
+}
+
+// unpinDeletedMessage drops the deleted message from pinned ones and broadcasts the new list
+func (c *ChatServiceImpl) unpinDeletedMessage(ctx context.Context, channel chat_models.Channel, tombstone chat_models.Message) error {
+	newChannel, err := c.channelRepo.UnpinMessage(ctx, channel.ID, tombstone.MessageID)
+	if err != nil {
+		return err
+	}
+	pinEvent := chat_models.Message{
+		MessageID:        tombstone.MessageID,
+		Event:            chat_models.PinEvent,
+		Type:             chat_models.DeleteMessageType,
+		ChannelID:        channel.ID,
+		UserID:           tombstone.UserID,
+		PinnedMessageIDs: newChannel.PinnedMessageIDs,
+	}
+	pinEvent.SetReceiverIDs(channel.UserIDs)
+	return c.msgPubRepo.PublishMessage(ctx, pinEvent)
+}
+
+// attachPinnedMessages loads pinned messages in the order they were pinned, deleted ones are skipped
+func (c *ChatServiceImpl) attachPinnedMessages(ctx context.Context, channel *chat_models.Channel) {
+	if len(channel.PinnedMessageIDs) == 0 {
+		return
+	}
+	msgs, err := c.msgRepo.GetMessagesByIDs(ctx, channel.PinnedMessageIDs)
+	if err != nil {
+		c.logger.Error().Err(err).Str("channel_id", channel.ID).Msg("unable to get pinned messages")
+		return
+	}
+	msgs = slices.DeleteFunc(msgs, func(msg chat_models.Message) bool {
+		return msg.IsDeleted() || msg.ChannelID != channel.ID
+	})
+	slices.SortFunc(msgs, func(a, b chat_models.Message) int {
+		return slices.Index(channel.PinnedMessageIDs, a.MessageID) - slices.Index(channel.PinnedMessageIDs, b.MessageID)
+	})
+	c.attachQuotes(ctx, msgs)
+	c.attachStudyMaterials(ctx, msgs)
+	channel.PinnedMessages = msgs
+}
@@ -0,0 +1,140 @@
+package chat_service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+	pb "github.com/Petr09Mitin/xrust-beze-back/proto/user"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ProcessTypingEvent relays typing status to other channel members, nothing is stored
+func (c *ChatServiceImpl) ProcessTypingEvent(ctx context.Context, msg chat_models.Message) error {
+	if msg.ChannelID == "" {
+		return custom_errors.ErrNoChannelID
+	}
+	if msg.Status == "" {
+		msg.Status = chat_models.TypingStartedStatus
+	}
+	if msg.Status != chat_models.TypingStartedStatus && msg.Status != chat_models.TypingStoppedStatus {
+		return custom_errors.ErrInvalidMessage
+	}
+	channel, err := c.channelRepo.GetChannelByID(ctx, msg.ChannelID)
+	if err != nil {
+		return err
+	}
+	if err = checkChannelMember(channel, msg.UserID); err != nil {
+		return err
+	}
+
+	typingEvent := chat_models.Message{
+		Event:     chat_models.TypingEvent,
+		Type:      chat_models.SendMessageType,
+		ChannelID: channel.ID,
+		UserID:    msg.UserID,
+		Status:    msg.Status,
+		CreatedAt: time.Now().Unix(),
+	}
+	typingEvent.SetReceiverIDs(excludeUserID(channel.UserIDs, msg.UserID))
+	if err = c.msgPubRepo.PublishEphemeral(ctx, typingEvent); err != nil {
+		c.logger.Err(err)
+		return custom_errors.ErrBroadcastingTextMessage
+	}
+	return nil
+}
+
+// UserConnected registers a new websocket connection, contacts are notified only about the first one
+func (c *ChatServiceImpl) UserConnected(ctx context.Context, userID, connID string) {
+	becameOnline, err := c.presenceRepo.AddConnection(ctx, userID, connID)
+	if err != nil {
+		c.logger.Error().Err(err).Str("user_id", userID).Msg("unable to add presence connection")
+		return
+	}
+	c.updateLastActiveAt(ctx, userID)
+	if becameOnline {
+		c.publishPresence(ctx, userID, chat_models.PresenceOnlineStatus)
+	}
+}
+
+// UserHeartbeat keeps the connection alive in presence store
+func (c *ChatServiceImpl) UserHeartbeat(ctx context.Context, userID, connID string) {
+	err := c.presenceRepo.RefreshConnection(ctx, userID, connID)
+	if err != nil {
+		c.logger.Error().Err(err).Str("user_id", userID).Msg("unable to refresh presence connection")
+	}
+}
+
+// UserDisconnected removes websocket connection, contacts are notified when the last one is closed
+func (c *ChatServiceImpl) UserDisconnected(ctx context.Context, userID, connID string) {
+	becameOffline, err := c.presenceRepo.RemoveConnection(ctx, userID, connID)
+	if err != nil {
+		c.logger.Error().Err(err).Str("user_id", userID).Msg("unable to remove presence connection")
+		return
+	}
+	c.updateLastActiveAt(ctx, userID)
+	if becameOffline {
+		c.publishPresence(ctx, userID, chat_models.PresenceOfflineStatus)
+	}
+}
+
+func (c *ChatServiceImpl) publishPresence(ctx context.Context, userID, status string) {
+	// limit 0 means no limit - presence goes to everyone the user has a conversation with
+	channels, err := c.channelRepo.GetChannelsByUserID(ctx, userID, 0, 0)
+	if err != nil {
+		c.logger.Error().Err(err).Str("user_id", userID).Msg("unable to get channels to publish presence")
+		return
+	}
+	contactIDs := make([]string, 0)
+	for _, channel := range channels {
+		for _, memberID := range channel.UserIDs {
+			if memberID != userID && !slices.Contains(contactIDs, memberID) {
+				contactIDs = append(contactIDs, memberID)
+			}
+		}
+	}
+	if len(contactIDs) == 0 {
+		return
+	}
+
+	presenceEvent := chat_models.Message{
+		Event:     chat_models.PresenceEvent,
+		Type:      chat_models.SendMessageType,
+		UserID:    userID,
+		Status:    status,
+		CreatedAt: time.Now().Unix(),
+	}
+	presenceEvent.SetReceiverIDs(contactIDs)
+	if err = c.msgPubRepo.PublishEphemeral(ctx, presenceEvent); err != nil {
+		c.logger.Error().Err(err).Str("user_id", userID).Msg("unable to publish presence")
+	}
+}
+
+func (c *ChatServiceImpl) updateLastActiveAt(ctx context.Context, userID string) {
+	_, err := c.userService.UpdateLastActiveAt(ctx, &pb.UpdateLastActiveAtRequest{
+		Id:           userID,
+		LastActiveAt: timestamppb.Now(),
+	})
+	if err != nil {
+		c.logger.Error().Err(err).Msg(fmt.Sprintf("unable to update last active at of user %s", userID))
+	}
+}
+
+// attachOnlineUsers fills online members of the channel, errors are logged and ignored
+func (c *ChatServiceImpl) attachOnlineUsers(ctx context.Context, channel *chat_models.Channel) {
+	onlineUserIDs, err := c.presenceRepo.GetOnlineUserIDs(ctx, channel.UserIDs)
+	if err != nil {
+		c.logger.Error().Err(err).Msg(fmt.Sprintf("unable to get online users of channel %s", channel.ID))
+		return
+	}
+	channel.OnlineUserIDs = onlineUserIDs
+}
+
+func excludeUserID(userIDs []string, userID string) []string {
+	return slices.DeleteFunc(slices.Clone(userIDs), func(id string) bool {
+		return id == userID
+	})
+}
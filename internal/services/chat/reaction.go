@@ -0,0 +1,66 @@
+package chat_service
+
+import (
+	"context"
+	"time"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+)
+
+// ProcessReactionEvent adds (send_message) or removes (delete_message) user's reaction to the message.
+// Result is broadcast as update_message with the full reactions map, no reactions field means none are left
+func (c *ChatServiceImpl) ProcessReactionEvent(ctx context.Context, msg chat_models.Message) error {
+	if msg.MessageID == "" {
+		return custom_errors.ErrNoMessageID
+	}
+	if !chat_models.IsValidReaction(msg.Reaction) {
+		return custom_errors.ErrInvalidReaction
+	}
+	oldMsg, err := c.msgRepo.GetMessageByID(ctx, msg.MessageID)
+	if err != nil {
+		return err
+	}
+	channel, err := c.channelRepo.GetChannelByID(ctx, oldMsg.ChannelID)
+	if err != nil {
+		return err
+	}
+	if err = checkChannelMember(channel, msg.UserID); err != nil {
+		return err
+	}
+	if oldMsg.IsDeleted() {
+		return custom_errors.ErrMessageDeleted
+	}
+
+	// updated_at is bumped, so reaction changes are replayed on reconnect like edits
+	updatedAt := time.Now().Unix()
+	var newMsg *chat_models.Message
+	switch msg.Type {
+	case chat_models.SendMessageType:
+		newMsg, err = c.msgRepo.AddReaction(ctx, oldMsg.MessageID, msg.Reaction, msg.UserID, updatedAt)
+	case chat_models.DeleteMessageType:
+		newMsg, err = c.msgRepo.RemoveReaction(ctx, oldMsg.MessageID, msg.Reaction, msg.UserID, updatedAt)
+	default:
+		return custom_errors.ErrInvalidMessageType
+	}
+	if err != nil {
+		return err
+	}
+
+	reactionEvent := chat_models.Message{
+		MessageID: newMsg.MessageID,
+		Event:     chat_models.ReactionEvent,
+		Type:      chat_models.UpdateMessageType,
+		ChannelID: channel.ID,
+		UserID:    msg.UserID,
+		Reaction:  msg.Reaction,
+		Reactions: newMsg.Reactions,
+		UpdatedAt: newMsg.UpdatedAt,
+	}
+	reactionEvent.SetReceiverIDs(channel.UserIDs)
+	if err = c.msgPubRepo.PublishMessage(ctx, reactionEvent); err != nil {
+		c.logger.Err(err)
+		return custom_errors.ErrBroadcastingTextMessage
+	}
+	return nil
+}
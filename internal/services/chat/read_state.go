@@ -0,0 +1,85 @@
+package chat_service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+)
+
+// ProcessReadEvent moves user's read marker in the channel forward and notifies other members
+func (c *ChatServiceImpl) ProcessReadEvent(ctx context.Context, msg chat_models.Message) error {
+	if msg.MessageID == "" {
+		return custom_errors.ErrNoMessageID
+	}
+	readMsg, err := c.msgRepo.GetMessageByID(ctx, msg.MessageID)
+	if err != nil {
+		return err
+	}
+	channel, err := c.channelRepo.GetChannelByID(ctx, readMsg.ChannelID)
+	if err != nil {
+		return err
+	}
+	if err = checkChannelMember(channel, msg.UserID); err != nil {
+		return err
+	}
+
+	updatedAt := time.Now().Unix()
+	moved, err := c.readStateRepo.UpsertReadState(ctx, chat_models.ReadState{
+		ChannelID:         channel.ID,
+		UserID:            msg.UserID,
+		LastReadMessageID: readMsg.MessageID,
+		LastReadAt:        readMsg.CreatedAt,
+		UpdatedAt:         updatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	if !moved {
+		// read marker never goes back, e.g. when older messages are shown on scroll
+		return nil
+	}
+
+	readEvent := chat_models.Message{
+		MessageID: readMsg.MessageID,
+		Event:     chat_models.ReadEvent,
+		Type:      chat_models.SendMessageType,
+		ChannelID: channel.ID,
+		UserID:    msg.UserID,
+		UpdatedAt: updatedAt,
+	}
+	readEvent.SetReceiverIDs(excludeUserID(channel.UserIDs, msg.UserID))
+	if err = c.msgPubRepo.PublishMessage(ctx, readEvent); err != nil {
+		c.logger.Err(err)
+		return custom_errors.ErrBroadcastingTextMessage
+	}
+	return nil
+}
+
+// attachReadStates fills unread counters for the user, errors are logged and the counters are left empty
+func (c *ChatServiceImpl) attachReadStates(ctx context.Context, userID string, channels []chat_models.Channel) {
+	channelIDs := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		channelIDs = append(channelIDs, channel.ID)
+	}
+	states, err := c.readStateRepo.GetReadStatesByUserID(ctx, userID, channelIDs)
+	if err != nil {
+		c.logger.Error().Err(err).Msg(fmt.Sprintf("unable to get read states of user %s", userID))
+		return
+	}
+	for i, channel := range channels {
+		var lastRead *chat_models.MessageCursor
+		if state, ok := states[channel.ID]; ok {
+			lastRead = state.Cursor()
+			channels[i].LastReadMessageID = state.LastReadMessageID
+		}
+		unread, err := c.msgRepo.CountUnreadMessages(ctx, channel.ID, userID, lastRead)
+		if err != nil {
+			c.logger.Error().Err(err).Msg(fmt.Sprintf("unable to count unread messages in channel %s", channel.ID))
+			continue
+		}
+		channels[i].UnreadCount = unread
+	}
+}
@@ -0,0 +1,73 @@
+package chat_service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+)
+
+const (
+	// MaxReplayEvents is the most missed events replayed on reconnect, with more the client has to resync over REST
+	MaxReplayEvents = 200
+)
+
+// ResolveReplaySince parses since of ws handshake: unix timestamp or id of the last seen message
+func (c *ChatServiceImpl) ResolveReplaySince(ctx context.Context, userID, since string) (int64, error) {
+	if ts, err := strconv.ParseInt(since, 10, 64); err == nil {
+		if ts < 0 {
+			return 0, custom_errors.ErrInvalidReplaySince
+		}
+		return ts, nil
+	}
+	msg, err := c.GetMessageByID(ctx, userID, since)
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrNotFound) {
+			return 0, custom_errors.ErrInvalidReplaySince
+		}
+		return 0, err
+	}
+	return msg.CreatedAt, nil
+}
+
+// GetMissedEvents returns new messages, edits and deletions (tombstones) in all user's channels since the timestamp,
+// the oldest first. Events of the same second are included, clients dedupe them by message id.
+// resync is true when the events can't be replayed completely
+func (c *ChatServiceImpl) GetMissedEvents(ctx context.Context, userID string, since int64) (events []chat_models.Message, resync bool, err error) {
+	channels, err := c.channelRepo.GetChannelsByUserID(ctx, userID, 0, 0)
+	if err != nil {
+		return nil, false, err
+	}
+	channelIDs := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		channelIDs = append(channelIDs, channel.ID)
+	}
+
+	changed, err := c.msgRepo.GetMessagesChangedSince(ctx, channelIDs, since, MaxReplayEvents+1)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(changed) > MaxReplayEvents {
+		return nil, true, nil
+	}
+
+	c.attachQuotes(ctx, changed)
+	c.attachStudyMaterials(ctx, changed)
+	events = make([]chat_models.Message, 0, len(changed))
+	for _, msg := range changed {
+		msg.Event = msg.KindEvent()
+		switch {
+		case msg.IsDeleted():
+			msg.Type = chat_models.DeleteMessageType
+		case msg.CreatedAt >= since:
+			msg.Type = chat_models.SendMessageType
+		default:
+			msg.Type = chat_models.UpdateMessageType
+		}
+		events = append(events, msg)
+	}
+
+	return events, false, nil
+}
@@ -0,0 +1,155 @@
+package chat_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+	"github.com/rs/zerolog"
+)
+
+func newReplayTestService(msgs []chat_models.Message) (*ChatServiceImpl, *fakeMsgRepo) {
+	msgRepo := &fakeMsgRepo{msgs: msgs}
+	return &ChatServiceImpl{
+		msgRepo: msgRepo,
+		channelRepo: &fakeChannelRepo{channels: map[string]chat_models.Channel{
+			"direct": {ID: "direct", UserIDs: []string{"alice", "bob"}},
+			"group":  {ID: "group", Type: chat_models.GroupChannelType, UserIDs: []string{"alice", "bob", "carol"}},
+			"other":  {ID: "other", UserIDs: []string{"bob", "carol"}},
+		}},
+		logger: zerolog.Nop(),
+	}, msgRepo
+}
+
+func TestResolveReplaySince(t *testing.T) {
+	c, _ := newReplayTestService([]chat_models.Message{
+		{MessageID: "seen", ChannelID: "direct", CreatedAt: 1700000000},
+		{MessageID: "foreign", ChannelID: "other", CreatedAt: 1700000100},
+	})
+	tests := []struct {
+		name    string
+		since   string
+		want    int64
+		wantErr error
+	}{
+		{name: "timestamp", since: "1700000050", want: 1700000050},
+		{name: "zero timestamp", since: "0", want: 0},
+		{name: "negative timestamp", since: "-1", wantErr: custom_errors.ErrInvalidReplaySince},
+		{name: "last seen message id", since: "seen", want: 1700000000},
+		{name: "unknown message id", since: "unknown", wantErr: custom_errors.ErrInvalidReplaySince},
+		{name: "message of a foreign channel", since: "foreign", wantErr: custom_errors.ErrNotChannelMember},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.ResolveReplaySince(context.Background(), "alice", tt.since)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolve: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetMissedEvents(t *testing.T) {
+	const since = 1000
+	tests := []struct {
+		name       string
+		changed    []chat_models.Message
+		wantTypes  []chat_models.MsgType
+		wantResync bool
+	}{
+		{
+			name: "new, edited and deleted messages",
+			changed: []chat_models.Message{
+				{MessageID: "edited", ChannelID: "direct", CreatedAt: since - 100, UpdatedAt: since + 1},
+				{MessageID: "new", ChannelID: "group", CreatedAt: since + 2, UpdatedAt: since + 2},
+				{MessageID: "deleted", ChannelID: "direct", CreatedAt: since + 3, UpdatedAt: since + 4, DeletedAt: since + 4},
+				{MessageID: "same second", ChannelID: "group", CreatedAt: since, UpdatedAt: since},
+			},
+			wantTypes: []chat_models.MsgType{
+				chat_models.UpdateMessageType,
+				chat_models.SendMessageType,
+				chat_models.DeleteMessageType,
+				chat_models.SendMessageType,
+			},
+		},
+		{
+			name:      "nothing missed",
+			changed:   []chat_models.Message{},
+			wantTypes: []chat_models.MsgType{},
+		},
+		{
+			name:      "exactly the cap",
+			changed:   newChangedMessages(MaxReplayEvents),
+			wantTypes: slices.Repeat([]chat_models.MsgType{chat_models.SendMessageType}, MaxReplayEvents),
+		},
+		{
+			name:       "over the cap",
+			changed:    newChangedMessages(MaxReplayEvents + 1),
+			wantResync: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, msgRepo := newReplayTestService(tt.changed)
+			events, resync, err := c.GetMissedEvents(context.Background(), "alice", since)
+			if err != nil {
+				t.Fatalf("get missed events: %v", err)
+			}
+			if !slices.Equal(msgRepo.changedSince.channelIDs, []string{"direct", "group"}) {
+				t.Fatalf("got channels %v, want only channels of the user", msgRepo.changedSince.channelIDs)
+			}
+			if msgRepo.changedSince.since != since || msgRepo.changedSince.limit != MaxReplayEvents+1 {
+				t.Fatalf("got since %d and limit %d", msgRepo.changedSince.since, msgRepo.changedSince.limit)
+			}
+			if resync != tt.wantResync {
+				t.Fatalf("got resync %t, want %t", resync, tt.wantResync)
+			}
+			if tt.wantResync {
+				if events != nil {
+					t.Fatalf("got %d events with resync", len(events))
+				}
+				return
+			}
+			gotTypes := make([]chat_models.MsgType, 0, len(events))
+			for i, event := range events {
+				if event.MessageID != tt.changed[i].MessageID {
+					t.Fatalf("event %d is %s, want %s", i, event.MessageID, tt.changed[i].MessageID)
+				}
+				if event.Event != chat_models.TextMsgEvent {
+					t.Fatalf("event %d has event %s", i, event.Event)
+				}
+				gotTypes = append(gotTypes, event.Type)
+			}
+			if !slices.Equal(gotTypes, tt.wantTypes) {
+				t.Fatalf("got types %v, want %v", gotTypes, tt.wantTypes)
+			}
+		})
+	}
+}
+
+func newChangedMessages(count int) []chat_models.Message {
+	msgs := make([]chat_models.Message, 0, count)
+	for i := 0; i < count; i++ {
+		msgs = append(msgs, chat_models.Message{
+			MessageID: fmt.Sprintf("m%d", i),
+			ChannelID: "direct",
+			Payload:   "hello",
+			CreatedAt: int64(1000 + i),
+			UpdatedAt: int64(1000 + i),
+		})
+	}
+	return msgs
+}
@@ -0,0 +1,88 @@
+package chat_service
+
+import (
+	"context"
+	"errors"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+)
+
+// getReplyParent returns the message being replied to, it must belong to the same channel
+func (c *ChatServiceImpl) getReplyParent(ctx context.Context, channel chat_models.Channel, replyToMessageID string) (*chat_models.Message, error) {
+	parent, err := c.msgRepo.GetMessageByID(ctx, replyToMessageID)
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrNotFound) {
+			return nil, custom_errors.ErrReplyMessageNotFound
+		}
+		return nil, err
+	}
+	if err = checkMessageInChannel(*parent, channel); err != nil {
+		return nil, err
+	}
+	if parent.IsDeleted() {
+		return nil, custom_errors.ErrMessageDeleted
+	}
+	return parent, nil
+}
+
+// attachQuote fills preview of the replied message if it still exists
+func (c *ChatServiceImpl) attachQuote(ctx context.Context, msg *chat_models.Message) {
+	if msg.ReplyToMessageID == "" {
+		return
+	}
+	parent, err := c.msgRepo.GetMessageByID(ctx, msg.ReplyToMessageID)
+	if err != nil {
+		if !errors.Is(err, custom_errors.ErrNotFound) {
+			c.logger.Error().Err(err).Str("message_id", msg.ReplyToMessageID).Msg("unable to get replied message")
+		}
+		return
+	}
+	msg.ReplyTo = chat_models.NewQuotedMessage(*parent)
+}
+
+// attachQuotes fills previews of replied messages, deleted parents are left without preview
+func (c *ChatServiceImpl) attachQuotes(ctx context.Context, msgs []chat_models.Message) {
+	parentIDs := make([]string, 0)
+	for _, msg := range msgs {
+		if msg.ReplyToMessageID != "" {
+			parentIDs = append(parentIDs, msg.ReplyToMessageID)
+		}
+	}
+	if len(parentIDs) == 0 {
+		return
+	}
+	parents, err := c.msgRepo.GetMessagesByIDs(ctx, parentIDs)
+	if err != nil {
+		c.logger.Error().Err(err).Msg("unable to get replied messages")
+		return
+	}
+	parentsByID := make(map[string]chat_models.Message, len(parents))
+	for _, parent := range parents {
+		parentsByID[parent.MessageID] = parent
+	}
+	for i, msg := range msgs {
+		if parent, ok := parentsByID[msg.ReplyToMessageID]; ok {
+			msgs[i].ReplyTo = chat_models.NewQuotedMessage(parent)
+		}
+	}
+}
+
+// getStructurizationQuestion uses the replied message as the question,
+// without reply (or if the parent is deleted) the previous message in the channel is taken
+func (c *ChatServiceImpl) getStructurizationQuestion(ctx context.Context, msg chat_models.Message) (string, error) {
+	if msg.ReplyToMessageID != "" {
+		parent, err := c.msgRepo.GetMessageByID(ctx, msg.ReplyToMessageID)
+		if err != nil && !errors.Is(err, custom_errors.ErrNotFound) {
+			return "", err
+		}
+		if err == nil && !parent.IsDeleted() {
+			return c.concatenateMessages([]chat_models.Message{*parent}), nil
+		}
+	}
+	prevMessages, err := c.msgRepo.GetPreviousMessagesByMessageCreatedAt(ctx, msg.ChannelID, msg.CreatedAt, 1)
+	if err != nil {
+		return "", err
+	}
+	return c.concatenateMessages(prevMessages), nil
+}
@@ -0,0 +1,84 @@
+package chat_service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+)
+
+const (
+	maxScheduleAhead             = 365 * 24 * time.Hour
+	maxScheduledMessagesPageSize = 100
+)
+
+// ScheduleMessage saves the message or session reminder, schedulerd posts it to the channel at send_at
+func (c *ChatServiceImpl) ScheduleMessage(ctx context.Context, userID string, req chat_models.ScheduledMessageRequest) (*chat_models.ScheduledMessage, error) {
+	now := time.Now().Unix()
+	req.Payload = strings.TrimSpace(req.Payload)
+	switch req.Kind {
+	case chat_models.ScheduledTextKind:
+		if req.Payload == "" {
+			return nil, custom_errors.ErrInvalidMessage
+		}
+		req.SessionAt = 0
+	case chat_models.ScheduledReminderKind:
+		if req.SessionAt < req.SendAt {
+			return nil, custom_errors.ErrInvalidSessionTime
+		}
+	default:
+		return nil, custom_errors.ErrInvalidScheduledMessageKind
+	}
+	if req.SendAt <= now || req.SendAt > now+int64(maxScheduleAhead.Seconds()) {
+		return nil, custom_errors.ErrInvalidScheduleTime
+	}
+	if req.ChannelID == "" {
+		return nil, custom_errors.ErrNoChannelID
+	}
+	channel, err := c.channelRepo.GetChannelByID(ctx, req.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkChannelMember(channel, userID); err != nil {
+		return nil, err
+	}
+	blocks := c.getBlockRelations(ctx, userID)
+	if err = blocks.checkChannel(channel); err != nil {
+		return nil, err
+	}
+
+	sm, err := c.scheduledRepo.InsertScheduledMessage(ctx, chat_models.ScheduledMessage{
+		Kind:          req.Kind,
+		ChannelID:     channel.ID,
+		UserID:        userID,
+		Payload:       req.Payload,
+		SessionAt:     req.SessionAt,
+		SendAt:        req.SendAt,
+		Status:        chat_models.PendingScheduledStatus,
+		NextAttemptAt: req.SendAt,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &sm, nil
+}
+
+// GetScheduledMessages returns pending messages of the user, in the channel if channelID is set
+func (c *ChatServiceImpl) GetScheduledMessages(ctx context.Context, userID, channelID string) ([]chat_models.ScheduledMessage, error) {
+	return c.scheduledRepo.GetPendingByUserID(ctx, userID, channelID, maxScheduledMessagesPageSize)
+}
+
+func (c *ChatServiceImpl) CancelScheduledMessage(ctx context.Context, userID, scheduledMessageID string) error {
+	sm, err := c.scheduledRepo.GetScheduledMessageByID(ctx, scheduledMessageID)
+	if err != nil {
+		return err
+	}
+	if sm.UserID != userID {
+		return custom_errors.ErrNotMessageAuthor
+	}
+	return c.scheduledRepo.Cancel(ctx, scheduledMessageID, time.Now().Unix())
+}
@@ -0,0 +1,208 @@
+package chat_service
+
+import (
+	"context"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+)
+
+const (
+	maxSearchPageSize    = 100
+	maxSearchQueryLength = 256
+	// runes of context kept around the first match in a snippet
+	searchSnippetContext = 60
+)
+
+// SearchMessages searches the text of messages in all channels of the user, newest first
+func (c *ChatServiceImpl) SearchMessages(ctx context.Context, userID, text, before string, limit int64) (*chat_models.SearchPage, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, custom_errors.ErrEmptySearchQuery
+	}
+	if utf8.RuneCountInString(text) > maxSearchQueryLength {
+		return nil, custom_errors.ErrSearchQueryTooLong
+	}
+	if limit <= 0 || limit > maxSearchPageSize {
+		limit = maxSearchPageSize
+	}
+	query := chat_models.MessagesQuery{
+		Limit: limit,
+	}
+	var err error
+	if before != "" {
+		query.Before, err = chat_models.DecodeMessageCursor(before)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	channels, err := c.channelRepo.GetChannelsByUserID(ctx, userID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	channelsByID := make(map[string]*chat_models.Channel, len(channels))
+	channelIDs := make([]string, 0, len(channels))
+	for i, channel := range channels {
+		channelsByID[channel.ID] = &channels[i]
+		channelIDs = append(channelIDs, channel.ID)
+	}
+
+	msgs, err := c.msgRepo.SearchMessages(ctx, channelIDs, text, query)
+	if err != nil {
+		return nil, err
+	}
+	c.attachQuotes(ctx, msgs)
+	c.attachStudyMaterials(ctx, msgs)
+
+	terms := getSearchTerms(text)
+	enriched := make(map[string]bool, len(msgs))
+	page := &chat_models.SearchPage{
+		Results: make([]chat_models.SearchResult, 0, len(msgs)),
+	}
+	for _, msg := range msgs {
+		channel := channelsByID[msg.ChannelID]
+		// only channels found in results are enriched with members the same way as in channels list
+		if channel != nil && !enriched[channel.ID] {
+			c.attachUsers(ctx, channel)
+			c.attachOnlineUsers(ctx, channel)
+			enriched[channel.ID] = true
+		}
+		result := chat_models.SearchResult{
+			Message: msg,
+			Channel: channel,
+		}
+		result.Field, result.Snippet, result.Highlights = buildSearchSnippet(msg, terms)
+		page.Results = append(page.Results, result)
+	}
+	if int64(len(msgs)) == query.Limit {
+		page.NextCursor = chat_models.NewMessageCursor(msgs[len(msgs)-1]).Encode()
+	}
+
+	return page, nil
+}
+
+// getSearchTerms splits the query into lowercase words, symbols of mongo syntax (quotes, minus) are dropped
+func getSearchTerms(text string) [][]rune {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := make([][]rune, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, toLowerRunes([]rune(word)))
+	}
+	return terms
+}
+
+// toLowerRunes lowers rune by rune, so offsets stay the same as in the original text
+func toLowerRunes(text []rune) []rune {
+	res := make([]rune, len(text))
+	for i, r := range text {
+		res[i] = unicode.ToLower(r)
+	}
+	return res
+}
+
+// buildSearchSnippet finds the first searchable field containing any of the terms
+// and cuts the text around the first match. Mongo matches stems, so nothing may be found here,
+// then the beginning of the first non-empty field is returned without highlights
+func buildSearchSnippet(msg chat_models.Message, terms [][]rune) (chat_models.SearchField, string, []chat_models.SearchHighlight) {
+	fields := []struct {
+		name chat_models.SearchField
+		text string
+	}{
+		{chat_models.PayloadSearchField, msg.Payload},
+		{chat_models.RecognizedVoiceSearchField, msg.RecognizedVoice},
+		{chat_models.StructurizedSearchField, msg.Structurized},
+	}
+	for _, field := range fields {
+		text := []rune(field.text)
+		highlights := findHighlights(toLowerRunes(text), terms)
+		if len(highlights) > 0 {
+			snippet, highlights := cutSnippet(text, highlights)
+			return field.name, snippet, highlights
+		}
+	}
+	for _, field := range fields {
+		if field.text != "" {
+			snippet, _ := cutSnippet([]rune(field.text), nil)
+			return field.name, snippet, []chat_models.SearchHighlight{}
+		}
+	}
+	return "", "", []chat_models.SearchHighlight{}
+}
+
+// findHighlights returns ordered non-overlapping matches of the terms at word starts
+func findHighlights(text []rune, terms [][]rune) []chat_models.SearchHighlight {
+	highlights := make([]chat_models.SearchHighlight, 0)
+	for i := 0; i < len(text); i++ {
+		if i > 0 && (unicode.IsLetter(text[i-1]) || unicode.IsDigit(text[i-1])) {
+			continue
+		}
+		longest := 0
+		for _, term := range terms {
+			if len(term) > longest && hasRunePrefix(text[i:], term) {
+				longest = len(term)
+			}
+		}
+		if longest == 0 {
+			continue
+		}
+		// highlight the whole word, e.g. "structur" in "structurization"
+		end := i + longest
+		for end < len(text) && (unicode.IsLetter(text[end]) || unicode.IsDigit(text[end])) {
+			end++
+		}
+		highlights = append(highlights, chat_models.SearchHighlight{Start: i, End: end})
+		i = end - 1
+	}
+	return highlights
+}
+
+func hasRunePrefix(text, prefix []rune) bool {
+	if len(text) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if text[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// cutSnippet keeps context around the first highlight, highlights are shifted to the snippet offsets
+func cutSnippet(text []rune, highlights []chat_models.SearchHighlight) (string, []chat_models.SearchHighlight) {
+	start := 0
+	if len(highlights) > 0 {
+		start = max(0, highlights[0].Start-searchSnippetContext)
+	}
+	end := min(len(text), start+2*searchSnippetContext)
+	if len(highlights) > 0 {
+		end = min(len(text), max(end, highlights[0].End+searchSnippetContext))
+	}
+
+	prefix := ""
+	if start > 0 {
+		prefix = "…"
+	}
+	suffix := ""
+	if end < len(text) {
+		suffix = "…"
+	}
+	shift := utf8.RuneCountInString(prefix) - start
+	res := make([]chat_models.SearchHighlight, 0, len(highlights))
+	for _, highlight := range highlights {
+		if highlight.Start < start || highlight.End > end {
+			continue
+		}
+		res = append(res, chat_models.SearchHighlight{
+			Start: highlight.Start + shift,
+			End:   highlight.End + shift,
+		})
+	}
+	return prefix + string(text[start:end]) + suffix, res
+}
@@ -0,0 +1,38 @@
+package chat_service
+
+import (
+	"context"
+	"time"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+)
+
+// structurizationInFlightTTL is longer than structurizationd spends on a task with all retries,
+// so a mark left by a crashed worker is taken over by the next request
+const structurizationInFlightTTL = 10 * time.Minute
+
+// publishStoredStructurization sends already generated explanation to the user without calling the explanator
+func (c *ChatServiceImpl) publishStoredStructurization(ctx context.Context, msg chat_models.Message, userID string) error {
+	msg.SetReceiverIDs([]string{userID})
+	msg.Type = chat_models.UpdateMessageType
+	msg.Event = chat_models.StructurizationEvent
+	msg.Status = chat_models.StructurizationDoneStatus
+	return c.msgPubRepo.PublishEphemeral(ctx, msg)
+}
+
+// saveStructurization stores explanation found in the cache as a new version and notifies channel members
+func (c *ChatServiceImpl) saveStructurization(ctx context.Context, oldMsg chat_models.Message, structurized, hash string) error {
+	now := time.Now().Unix()
+	return c.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		updated, err := c.msgRepo.SetStructurized(ctx, oldMsg.MessageID, oldMsg.NewStructurizedVersions(structurized, hash, now), now)
+		if err != nil {
+			return err
+		}
+		newMsg := *updated
+		newMsg.ReceiverIDs = oldMsg.ReceiverIDs
+		newMsg.Type = chat_models.UpdateMessageType
+		newMsg.Event = chat_models.StructurizationEvent
+		newMsg.Status = chat_models.StructurizationDoneStatus
+		return c.msgPubRepo.PublishMessage(ctx, newMsg)
+	})
+}
@@ -0,0 +1,156 @@
+package chat_service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+	studymaterialpb "github.com/Petr09Mitin/xrust-beze-back/proto/study_material"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ProcessStudyMaterialMessage shares the study material into the channel.
+// Only the id is stored with the message, the card is rendered from the study material service on read.
+// Returns id of the persisted message
+func (c *ChatServiceImpl) ProcessStudyMaterialMessage(ctx context.Context, msg chat_models.Message) (string, error) {
+	var newMsg chat_models.Message
+	var err error
+
+	switch msg.Type {
+	case chat_models.SendMessageType:
+		newMsg, err = c.createStudyMaterialMessage(ctx, msg)
+	case chat_models.DeleteMessageType:
+		newMsg, err = c.deleteTextMessage(ctx, msg)
+	default:
+		return "", custom_errors.ErrInvalidMessageType
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return newMsg.MessageID, nil
+}
+
+func (c *ChatServiceImpl) createStudyMaterialMessage(ctx context.Context, msg chat_models.Message) (chat_models.Message, error) {
+	studyMaterialID := strings.TrimSpace(msg.StudyMaterialID)
+	if studyMaterialID == "" {
+		return chat_models.Message{}, custom_errors.ErrNoMaterialID
+	}
+	card, err := c.getStudyMaterialCard(ctx, studyMaterialID)
+	if err != nil {
+		return chat_models.Message{}, err
+	}
+
+	blocks := c.getBlockRelations(ctx, msg.UserID)
+	channel, err := c.getOrCreateMessageChannel(ctx, msg, blocks)
+	if err != nil {
+		return chat_models.Message{}, err
+	}
+
+	var replyTo *chat_models.QuotedMessage
+	if msg.ReplyToMessageID != "" {
+		parent, err := c.getReplyParent(ctx, channel, msg.ReplyToMessageID)
+		if err != nil {
+			return chat_models.Message{}, err
+		}
+		replyTo = chat_models.NewQuotedMessage(*parent)
+	}
+
+	createdAt := time.Now().Unix()
+	newMsg := chat_models.Message{
+		Event:            chat_models.StudyMaterialEvent,
+		Type:             msg.Type,
+		ChannelID:        channel.ID,
+		UserID:           msg.UserID,
+		PeerID:           msg.PeerID,
+		Payload:          msg.Payload,
+		StudyMaterialID:  card.ID,
+		StudyMaterial:    card,
+		ReplyToMessageID: msg.ReplyToMessageID,
+		ReplyTo:          replyTo,
+		CreatedAt:        createdAt,
+		UpdatedAt:        createdAt,
+	}
+	newMsg.SetReceiverIDs(channel.UserIDs)
+	newMsg.MutedReceiverIDs = channel.MutedUserIDs
+	blocks.excludeReceivers(&newMsg)
+	err = c.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		inserted, err := c.msgRepo.InsertMessage(ctx, newMsg)
+		if err != nil {
+			return err
+		}
+		err = c.msgPubRepo.PublishMessage(ctx, inserted)
+		if err != nil {
+			return err
+		}
+		newMsg = inserted
+		return nil
+	})
+	if err != nil {
+		c.logger.Error().Err(err).Str("channel_id", channel.ID).Msg("unable to save study material message")
+		return chat_models.Message{}, custom_errors.ErrBroadcastingTextMessage
+	}
+	return newMsg, nil
+}
+
+func (c *ChatServiceImpl) getStudyMaterialCard(ctx context.Context, studyMaterialID string) (*chat_models.StudyMaterialCard, error) {
+	res, err := c.studyMaterialService.GetStudyMaterialByID(ctx, &studymaterialpb.GetStudyMaterialByIDRequest{
+		Id: studyMaterialID,
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound, codes.InvalidArgument:
+			return nil, custom_errors.ErrStudyMaterialNotFound
+		default:
+			c.logger.Error().Err(err).Str("study_material_id", studyMaterialID).Msg("unable to get study material")
+			return nil, err
+		}
+	}
+	material := res.GetStudyMaterial()
+	if material == nil {
+		return nil, custom_errors.ErrStudyMaterialNotFound
+	}
+	return &chat_models.StudyMaterialCard{
+		ID:       material.GetId(),
+		Name:     material.GetName(),
+		Filename: material.GetFilename(),
+		Tags:     material.GetTags(),
+		Author: chat_models.StudyMaterialAuthor{
+			ID:       material.GetAuthorId(),
+			Username: material.GetAuthor().GetUsername(),
+			Avatar:   material.GetAuthor().GetAvatar(),
+		},
+	}, nil
+}
+
+// attachStudyMaterial renders the card of the shared study material, deleted materials are left without card
+func (c *ChatServiceImpl) attachStudyMaterial(ctx context.Context, msg *chat_models.Message) {
+	if msg.StudyMaterialID == "" || msg.StudyMaterial != nil {
+		return
+	}
+	card, err := c.getStudyMaterialCard(ctx, msg.StudyMaterialID)
+	if err != nil {
+		return
+	}
+	msg.StudyMaterial = card
+}
+
+// attachStudyMaterials renders cards of the shared study materials, every material is requested once
+func (c *ChatServiceImpl) attachStudyMaterials(ctx context.Context, msgs []chat_models.Message) {
+	cards := make(map[string]*chat_models.StudyMaterialCard)
+	for i, msg := range msgs {
+		if msg.StudyMaterialID == "" {
+			continue
+		}
+		card, ok := cards[msg.StudyMaterialID]
+		if !ok {
+			// failed requests are remembered too, so the page does not wait for them again
+			card, _ = c.getStudyMaterialCard(ctx, msg.StudyMaterialID)
+			cards[msg.StudyMaterialID] = card
+		}
+		msgs[i].StudyMaterial = card
+	}
+}
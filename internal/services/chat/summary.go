@@ -0,0 +1,58 @@
+package chat_service
+
+import (
+	"context"
+	"time"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+)
+
+const (
+	defaultSummaryWindow = 24 * time.Hour
+	maxSummaryWindow     = 7 * 24 * time.Hour
+)
+
+// RequestChannelSummary asks structurizationd to summarize the channel messages in the window.
+// Summary is delivered later as a new pinned message of the requester
+func (c *ChatServiceImpl) RequestChannelSummary(ctx context.Context, userID, channelID string, req chat_models.SummaryRequest) error {
+	if req.To == 0 {
+		req.To = time.Now().Unix()
+	}
+	if req.From == 0 {
+		req.From = req.To - int64(defaultSummaryWindow.Seconds())
+	}
+	if req.From >= req.To {
+		return custom_errors.ErrInvalidSummaryWindow
+	}
+	if req.To-req.From > int64(maxSummaryWindow.Seconds()) {
+		return custom_errors.ErrSummaryWindowTooLong
+	}
+	channel, err := c.channelRepo.GetChannelByID(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	if err = checkChannelMember(channel, userID); err != nil {
+		return err
+	}
+
+	c.attachUsers(ctx, &channel)
+	usernames := make(map[string]string, len(channel.Users))
+	for _, user := range channel.Users {
+		usernames[user.ID.Hex()] = user.Username
+	}
+	err = c.structurizationPub.PublishSummaryTask(ctx, chat_models.SummaryTask{
+		ChannelID:   channel.ID,
+		UserID:      userID,
+		From:        req.From,
+		To:          req.To,
+		Usernames:   usernames,
+		ReceiverIDs: channel.UserIDs,
+	})
+	if err != nil {
+		c.logger.Error().Err(err).Str("channel_id", channel.ID).Msg("unable to publish summary task")
+		return custom_errors.ErrStructurizationUnavailable
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package file
 import (
 	"context"
 	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+	filemodels "github.com/Petr09Mitin/xrust-beze-back/internal/models/file"
 	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/validation"
 	filerepo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/file"
 	"github.com/google/uuid"
@@ -11,14 +12,15 @@ import (
 )
 
 type FileService interface {
-	UploadTempFile(ctx context.Context, filepath string) (filename string, err error)
+	UploadTempFile(ctx context.Context, filepath, originalName string) (filename string, err error)
 	MoveTempFileToAvatars(ctx context.Context, filename string) (err error)
 	DeleteAvatar(ctx context.Context, filename string) (err error)
 	MoveTempFileToVoiceMessages(ctx context.Context, filename string) (err error)
 	DeleteVoiceMessage(ctx context.Context, filename string) (err error)
-	MoveTempFilesToAttachments(ctx context.Context, filenames []string) (err error)
+	MoveTempFilesToAttachments(ctx context.Context, filenames []string) (attachments []filemodels.AttachmentMetadata, err error)
 	DeleteAttachments(ctx context.Context, filenames []string) (err error)
 	CopyAttachmentToStudyMaterials(ctx context.Context, filename string) (string, error)
+	CopyAttachments(ctx context.Context, filenames []string) (newFilenames []string, err error)
 }
 
 type FileServiceImpl struct {
@@ -33,9 +35,9 @@ func NewFileService(fileRepo filerepo.FileRepo, logger zerolog.Logger) FileServi
 	}
 }
 
-func (f *FileServiceImpl) UploadTempFile(ctx context.Context, filepath string) (string, error) {
+func (f *FileServiceImpl) UploadTempFile(ctx context.Context, filepath, originalName string) (string, error) {
 	filename := uuid.New().String() + path.Ext(filepath)
-	return filename, f.fileRepo.UploadTemp(ctx, filepath, filename)
+	return filename, f.fileRepo.UploadTemp(ctx, filepath, filename, originalName)
 }
 
 func (f *FileServiceImpl) MoveTempFileToAvatars(ctx context.Context, filename string) error {
@@ -116,30 +118,38 @@ func (f *FileServiceImpl) DeleteVoiceMessage(ctx context.Context, filename strin
 	return nil
 }
 
-func (f *FileServiceImpl) MoveTempFilesToAttachments(ctx context.Context, filenames []string) error {
+// MoveTempFilesToAttachments returns metadata of the files in the same order as filenames
+func (f *FileServiceImpl) MoveTempFilesToAttachments(ctx context.Context, filenames []string) ([]filemodels.AttachmentMetadata, error) {
+	attachments := make([]filemodels.AttachmentMetadata, 0, len(filenames))
 	for _, filename := range filenames {
 		exists, err := f.fileRepo.CheckIfTempExists(ctx, filename)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if !exists {
-			return custom_errors.ErrFileNotFound
+			return nil, custom_errors.ErrFileNotFound
+		}
+		// metadata is collected from the temp object before it is moved
+		metadata, err := f.fileRepo.GetTempMetadata(ctx, filename)
+		if err != nil {
+			return nil, err
 		}
+		attachments = append(attachments, metadata)
 	}
 
 	err := f.fileRepo.CopyFromTempToAttachments(ctx, filenames)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	for _, filename := range filenames {
 		err = f.fileRepo.DeleteTemp(ctx, filename)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	return nil
+	return attachments, nil
 }
 
 func (f *FileServiceImpl) DeleteAttachments(ctx context.Context, filenames []string) error {
@@ -164,3 +174,35 @@ func (f *FileServiceImpl) DeleteAttachments(ctx context.Context, filenames []str
 func (f *FileServiceImpl) CopyAttachmentToStudyMaterials(ctx context.Context, filename string) (string, error) {
 	return filename, f.fileRepo.CopyFromAttachmentsToStudyMaterials(ctx, filename)
 }
+
+// CopyAttachments copies attachments under new names, so the copies live independently of the originals.
+// Returns new filenames in the same order as filenames
+func (f *FileServiceImpl) CopyAttachments(ctx context.Context, filenames []string) ([]string, error) {
+	for _, filename := range filenames {
+		exists, err := f.fileRepo.CheckIfAttachmentExists(ctx, filename)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, custom_errors.ErrFileNotFound
+		}
+	}
+
+	newFilenames := make([]string, 0, len(filenames))
+	for _, filename := range filenames {
+		newFilename := uuid.New().String() + path.Ext(filename)
+		err := f.fileRepo.CopyAttachment(ctx, filename, newFilename)
+		if err != nil {
+			f.logger.Error().Err(err).Str("filename", filename).Msg("failed to copy attachment")
+			// copies are not referenced by anyone yet, so they are removed not to leak
+			if len(newFilenames) > 0 {
+				if delErr := f.fileRepo.DeleteAttachments(ctx, newFilenames); delErr != nil {
+					f.logger.Error().Err(delErr).Strs("filenames", newFilenames).Msg("failed to delete copied attachments")
+				}
+			}
+			return nil, err
+		}
+		newFilenames = append(newFilenames, newFilename)
+	}
+	return newFilenames, nil
+}
@@ -0,0 +1,125 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"path"
+	"slices"
+	"testing"
+
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+	filerepo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/file"
+	"github.com/rs/zerolog"
+)
+
+var errCopyFailed = errors.New("copy failed")
+
+// fakeFileRepo keeps attachments by name, copies of failCopy fail
+type fakeFileRepo struct {
+	filerepo.FileRepo
+	attachments map[string]struct{}
+	failCopy    string
+	deleted     []string
+}
+
+func (r *fakeFileRepo) CheckIfAttachmentExists(_ context.Context, filename string) (bool, error) {
+	_, ok := r.attachments[filename]
+	return ok, nil
+}
+
+func (r *fakeFileRepo) CopyAttachment(_ context.Context, src, dst string) error {
+	if src == r.failCopy {
+		return errCopyFailed
+	}
+	r.attachments[dst] = struct{}{}
+	return nil
+}
+
+func (r *fakeFileRepo) DeleteAttachments(_ context.Context, filenames []string) error {
+	for _, filename := range filenames {
+		delete(r.attachments, filename)
+	}
+	r.deleted = append(r.deleted, filenames...)
+	return nil
+}
+
+func TestCopyAttachments(t *testing.T) {
+	tests := []struct {
+		name        string
+		filenames   []string
+		failCopy    string
+		wantErr     error
+		wantDeleted int
+	}{
+		{
+			name:      "all copied",
+			filenames: []string{"a.png", "b.pdf"},
+		},
+		{
+			name:      "nothing to copy",
+			filenames: []string{},
+		},
+		{
+			name:      "missing source copies nothing",
+			filenames: []string{"a.png", "missing.png"},
+			wantErr:   custom_errors.ErrFileNotFound,
+		},
+		{
+			name:      "first copy fails",
+			filenames: []string{"a.png", "b.pdf"},
+			failCopy:  "a.png",
+			wantErr:   errCopyFailed,
+		},
+		{
+			name:        "copies made before the failure are deleted",
+			filenames:   []string{"a.png", "b.pdf", "c.txt"},
+			failCopy:    "c.txt",
+			wantErr:     errCopyFailed,
+			wantDeleted: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileRepo := &fakeFileRepo{
+				attachments: map[string]struct{}{"a.png": {}, "b.pdf": {}, "c.txt": {}},
+				failCopy:    tt.failCopy,
+			}
+			f := NewFileService(fileRepo, zerolog.Nop())
+
+			copies, err := f.CopyAttachments(context.Background(), tt.filenames)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got %v, want %v", err, tt.wantErr)
+				}
+				if copies != nil {
+					t.Fatalf("got copies %v with error", copies)
+				}
+				if len(fileRepo.deleted) != tt.wantDeleted {
+					t.Fatalf("got %d deleted copies, want %d", len(fileRepo.deleted), tt.wantDeleted)
+				}
+				// only the originals are left
+				if len(fileRepo.attachments) != 3 {
+					t.Fatalf("got attachments %v, want only the originals", fileRepo.attachments)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("copy: %v", err)
+			}
+			if len(copies) != len(tt.filenames) {
+				t.Fatalf("got %d copies, want %d", len(copies), len(tt.filenames))
+			}
+			for i, copyName := range copies {
+				if slices.Contains(tt.filenames, copyName) {
+					t.Fatalf("copy %s overwrites the original", copyName)
+				}
+				if path.Ext(copyName) != path.Ext(tt.filenames[i]) {
+					t.Fatalf("copy %s lost the extension of %s", copyName, tt.filenames[i])
+				}
+				if _, ok := fileRepo.attachments[copyName]; !ok {
+					t.Fatalf("copy %s is not stored", copyName)
+				}
+			}
+		})
+	}
+}
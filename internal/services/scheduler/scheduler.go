@@ -0,0 +1,173 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/backoff"
+	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/mongotx"
+	channelrepo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/channel"
+	message_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/chat"
+	scheduled_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/scheduled"
+	pb "github.com/Petr09Mitin/xrust-beze-back/proto/user"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+)
+
+const (
+	// other schedulers skip the claimed message for this time
+	claimLease      = 60 * time.Second
+	minRetryBackoff = 5 * time.Second
+	maxRetryBackoff = 5 * time.Minute
+)
+
+type SchedulerService interface {
+	DispatchDue(ctx context.Context, batchSize int)
+}
+
+type UserService interface {
+	GetBlockedUsers(ctx context.Context, in *pb.GetBlockedUsersRequest, opts ...grpc.CallOption) (*pb.GetBlockedUsersResponse, error)
+}
+
+type SchedulerServiceImpl struct {
+	scheduledRepo scheduled_repo.ScheduledMessageRepo
+	msgRepo       message_repo.MessageRepo
+	msgPubRepo    message_repo.MessagePubRepo
+	channelRepo   channelrepo.ChannelRepository
+	userService   UserService
+	txManager     mongotx.TxManager
+	maxAttempts   int
+	logger        zerolog.Logger
+}
+
+func NewSchedulerService(
+	scheduledRepo scheduled_repo.ScheduledMessageRepo,
+	msgRepo message_repo.MessageRepo,
+	msgPubRepo message_repo.MessagePubRepo,
+	channelRepo channelrepo.ChannelRepository,
+	userService UserService,
+	txManager mongotx.TxManager,
+	maxAttempts int,
+	logger zerolog.Logger,
+) SchedulerService {
+	return &SchedulerServiceImpl{
+		scheduledRepo: scheduledRepo,
+		msgRepo:       msgRepo,
+		msgPubRepo:    msgPubRepo,
+		channelRepo:   channelRepo,
+		userService:   userService,
+		txManager:     txManager,
+		maxAttempts:   maxAttempts,
+		logger:        logger,
+	}
+}
+
+// DispatchDue posts up to batchSize due messages
+func (s *SchedulerServiceImpl) DispatchDue(ctx context.Context, batchSize int) {
+	for i := 0; i < batchSize; i++ {
+		if ctx.Err() != nil {
+			return
+		}
+		sm, err := s.scheduledRepo.ClaimDue(ctx, time.Now().Unix(), int64(claimLease.Seconds()))
+		if err != nil {
+			s.logger.Error().Err(err).Msg("unable to claim scheduled message")
+			return
+		}
+		if sm == nil {
+			return
+		}
+		s.dispatch(ctx, *sm)
+	}
+}
+
+func (s *SchedulerServiceImpl) dispatch(ctx context.Context, sm chat_models.ScheduledMessage) {
+	channel, err := s.channelRepo.GetChannelByID(ctx, sm.ChannelID)
+	if err == nil && !slices.Contains(channel.UserIDs, sm.UserID) {
+		err = custom_errors.ErrNotChannelMember
+	}
+	var blockedBy []string
+	if err == nil {
+		blockedBy, err = s.checkBlocks(ctx, sm.UserID, channel)
+	}
+	if err != nil {
+		// the author left the channel, it is removed or the peer is blocked, retrying is useless
+		final := errors.Is(err, custom_errors.ErrNotFound) || errors.Is(err, custom_errors.ErrNotChannelMember) ||
+			errors.Is(err, custom_errors.ErrUserBlocked)
+		s.markFailed(ctx, sm, err, final)
+		return
+	}
+
+	now := time.Now().Unix()
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		inserted, err := s.msgRepo.InsertMessage(ctx, sm.ToMessage(now))
+		if err != nil {
+			return err
+		}
+		inserted.SetReceiverIDs(channel.UserIDs)
+		for _, blockedByID := range blockedBy {
+			delete(inserted.ReceiverIDs, blockedByID)
+		}
+		inserted.MutedReceiverIDs = channel.MutedUserIDs
+		err = s.msgPubRepo.PublishMessage(ctx, inserted)
+		if err != nil {
+			return err
+		}
+		return s.scheduledRepo.MarkSent(ctx, sm.ID, inserted.MessageID, now)
+	})
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrScheduledMessageNotPending) {
+			// canceled while being sent
+			return
+		}
+		s.markFailed(ctx, sm, err, false)
+		return
+	}
+	s.logger.Info().Str("scheduled_message_id", sm.ID.Hex()).Str("channel_id", sm.ChannelID).Msg("scheduled message sent")
+}
+
+// checkBlocks applies blocks made after the message was scheduled, as chat does on send:
+// direct messages between blocked users fail, in groups members who blocked the author do not receive it.
+// Returns ids of members who blocked the author
+func (s *SchedulerServiceImpl) checkBlocks(ctx context.Context, userID string, channel chat_models.Channel) ([]string, error) {
+	res, err := s.userService.GetBlockedUsers(ctx, &pb.GetBlockedUsersRequest{
+		UserId: userID,
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Str("user_id", userID).Msg("unable to get blocked users")
+		return nil, custom_errors.ErrBlockedUsersUnavailable
+	}
+	if !channel.IsGroup() {
+		for _, peerID := range channel.UserIDs {
+			if peerID == userID {
+				continue
+			}
+			if slices.Contains(res.GetBlockedUserIds(), peerID) || slices.Contains(res.GetBlockedByUserIds(), peerID) {
+				return nil, custom_errors.ErrUserBlocked
+			}
+		}
+	}
+	return res.GetBlockedByUserIds(), nil
+}
+
+func (s *SchedulerServiceImpl) markFailed(ctx context.Context, sm chat_models.ScheduledMessage, cause error, final bool) {
+	now := time.Now()
+	sm.Attempts++
+	sm.LastError = cause.Error()
+	sm.NextAttemptAt = now.Add(backoff.Exponential(sm.Attempts, minRetryBackoff, maxRetryBackoff)).Unix()
+	sm.UpdatedAt = now.Unix()
+	if final || sm.Attempts >= s.maxAttempts {
+		sm.Status = chat_models.FailedScheduledStatus
+	}
+	s.logger.Error().Err(cause).
+		Str("scheduled_message_id", sm.ID.Hex()).
+		Int("attempts", sm.Attempts).
+		Msg("unable to send scheduled message")
+	err := s.scheduledRepo.MarkFailed(ctx, sm)
+	if err != nil {
+		s.logger.Error().Err(err).Str("scheduled_message_id", sm.ID.Hex()).Msg("unable to mark scheduled message as failed")
+	}
+}
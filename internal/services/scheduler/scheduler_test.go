@@ -0,0 +1,359 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+	channelrepo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/channel"
+	message_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/chat"
+	scheduled_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/scheduled"
+	pb "github.com/Petr09Mitin/xrust-beze-back/proto/user"
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"google.golang.org/grpc"
+)
+
+type claim struct {
+	now, lease int64
+}
+
+// fakeScheduledRepo keeps messages in memory, ClaimDue and Mark* match the conditions of the mongo queries
+type fakeScheduledRepo struct {
+	scheduled_repo.ScheduledMessageRepo
+	msgs   []*chat_models.ScheduledMessage
+	claims []claim
+}
+
+func (r *fakeScheduledRepo) ClaimDue(_ context.Context, now, lease int64) (*chat_models.ScheduledMessage, error) {
+	r.claims = append(r.claims, claim{now: now, lease: lease})
+	var due *chat_models.ScheduledMessage
+	for _, sm := range r.msgs {
+		if sm.Status != chat_models.PendingScheduledStatus || sm.NextAttemptAt > now {
+			continue
+		}
+		if due == nil || sm.NextAttemptAt < due.NextAttemptAt {
+			due = sm
+		}
+	}
+	if due == nil {
+		return nil, nil
+	}
+	due.NextAttemptAt = now + lease
+	res := *due
+	return &res, nil
+}
+
+func (r *fakeScheduledRepo) MarkSent(_ context.Context, id bson.ObjectID, messageID string, sentAt int64) error {
+	sm := r.get(id)
+	if sm == nil || sm.Status != chat_models.PendingScheduledStatus {
+		return custom_errors.ErrScheduledMessageNotPending
+	}
+	sm.Status = chat_models.SentScheduledStatus
+	sm.MessageID = messageID
+	sm.UpdatedAt = sentAt
+	return nil
+}
+
+func (r *fakeScheduledRepo) MarkFailed(_ context.Context, failed chat_models.ScheduledMessage) error {
+	sm := r.get(failed.ID)
+	if sm == nil || sm.Status != chat_models.PendingScheduledStatus {
+		return nil
+	}
+	*sm = failed
+	return nil
+}
+
+func (r *fakeScheduledRepo) get(id bson.ObjectID) *chat_models.ScheduledMessage {
+	for _, sm := range r.msgs {
+		if sm.ID == id {
+			return sm
+		}
+	}
+	return nil
+}
+
+type fakeMsgRepo struct {
+	message_repo.MessageRepo
+	inserted []chat_models.Message
+}
+
+func (r *fakeMsgRepo) InsertMessage(_ context.Context, msg chat_models.Message) (chat_models.Message, error) {
+	msg.MessageID = fmt.Sprintf("msg-%d", len(r.inserted))
+	r.inserted = append(r.inserted, msg)
+	return msg, nil
+}
+
+type fakeMsgPubRepo struct {
+	published []chat_models.Message
+	err       error
+}
+
+func (r *fakeMsgPubRepo) PublishMessage(_ context.Context, msg chat_models.Message) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.published = append(r.published, msg)
+	return nil
+}
+
+func (r *fakeMsgPubRepo) PublishEphemeral(_ context.Context, msg chat_models.Message) error {
+	return r.PublishMessage(context.Background(), msg)
+}
+
+type fakeChannelRepo struct {
+	channelrepo.ChannelRepository
+	channels map[string]chat_models.Channel
+}
+
+func (r *fakeChannelRepo) GetChannelByID(_ context.Context, id string) (chat_models.Channel, error) {
+	channel, ok := r.channels[id]
+	if !ok {
+		return chat_models.Channel{}, custom_errors.ErrNotFound
+	}
+	return channel, nil
+}
+
+type fakeUserService struct {
+	res *pb.GetBlockedUsersResponse
+	err error
+}
+
+func (s *fakeUserService) GetBlockedUsers(_ context.Context, _ *pb.GetBlockedUsersRequest, _ ...grpc.CallOption) (*pb.GetBlockedUsersResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.res == nil {
+		return &pb.GetBlockedUsersResponse{}, nil
+	}
+	return s.res, nil
+}
+
+type fakeTxManager struct{}
+
+func (fakeTxManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func newTestChannels() map[string]chat_models.Channel {
+	return map[string]chat_models.Channel{
+		"direct": {ID: "direct", UserIDs: []string{"alice", "bob"}},
+		"group":  {ID: "group", Type: chat_models.GroupChannelType, UserIDs: []string{"alice", "bob", "carol"}},
+	}
+}
+
+func newScheduledMessage(channelID string, nextAttemptAt int64, attempts int) *chat_models.ScheduledMessage {
+	return &chat_models.ScheduledMessage{
+		ID:            bson.NewObjectID(),
+		Kind:          chat_models.ScheduledTextKind,
+		ChannelID:     channelID,
+		UserID:        "alice",
+		Payload:       "hello",
+		SendAt:        nextAttemptAt,
+		Status:        chat_models.PendingScheduledStatus,
+		Attempts:      attempts,
+		NextAttemptAt: nextAttemptAt,
+	}
+}
+
+func TestDispatchDueClaimsWithLease(t *testing.T) {
+	now := time.Now().Unix()
+	tests := []struct {
+		name      string
+		msgs      []*chat_models.ScheduledMessage
+		batchSize int
+		wantSent  int
+		wantClaim int
+	}{
+		{
+			name: "due messages are sent, future are not claimed",
+			msgs: []*chat_models.ScheduledMessage{
+				newScheduledMessage("direct", now-10, 0),
+				newScheduledMessage("group", now-5, 0),
+				newScheduledMessage("direct", now+3600, 0),
+			},
+			batchSize: 10,
+			wantSent:  2,
+			// the last claim finds nothing due
+			wantClaim: 3,
+		},
+		{
+			name: "batch size limits claims",
+			msgs: []*chat_models.ScheduledMessage{
+				newScheduledMessage("direct", now-10, 0),
+				newScheduledMessage("direct", now-9, 0),
+				newScheduledMessage("direct", now-8, 0),
+			},
+			batchSize: 2,
+			wantSent:  2,
+			wantClaim: 2,
+		},
+		{
+			name:      "nothing due",
+			msgs:      []*chat_models.ScheduledMessage{newScheduledMessage("direct", now+60, 0)},
+			batchSize: 10,
+			wantClaim: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduledRepo := &fakeScheduledRepo{msgs: tt.msgs}
+			msgPubRepo := &fakeMsgPubRepo{}
+			s := NewSchedulerService(scheduledRepo, &fakeMsgRepo{}, msgPubRepo, &fakeChannelRepo{channels: newTestChannels()},
+				&fakeUserService{}, fakeTxManager{}, 5, zerolog.Nop())
+
+			s.DispatchDue(context.Background(), tt.batchSize)
+
+			if len(scheduledRepo.claims) != tt.wantClaim {
+				t.Fatalf("got %d claims, want %d", len(scheduledRepo.claims), tt.wantClaim)
+			}
+			for _, c := range scheduledRepo.claims {
+				if c.lease != int64(claimLease.Seconds()) || c.now < now {
+					t.Fatalf("got claim %+v, want lease %v from now", c, claimLease)
+				}
+			}
+			if len(msgPubRepo.published) != tt.wantSent {
+				t.Fatalf("got %d published, want %d", len(msgPubRepo.published), tt.wantSent)
+			}
+			sent := 0
+			for _, sm := range scheduledRepo.msgs {
+				if sm.Status == chat_models.SentScheduledStatus {
+					sent++
+				}
+			}
+			if sent != tt.wantSent {
+				t.Fatalf("got %d marked as sent, want %d", sent, tt.wantSent)
+			}
+		})
+	}
+}
+
+func TestDispatchRetriesWithBackoff(t *testing.T) {
+	const maxAttempts = 5
+	tests := []struct {
+		name         string
+		channelID    string
+		attempts     int
+		userService  *fakeUserService
+		publishErr   error
+		wantStatus   chat_models.ScheduledMessageStatus
+		wantAttempts int
+		wantBackoff  time.Duration
+		wantErr      error
+	}{
+		{
+			name:         "first failure retries after min backoff",
+			channelID:    "direct",
+			publishErr:   errors.New("kafka is down"),
+			wantStatus:   chat_models.PendingScheduledStatus,
+			wantAttempts: 1,
+			wantBackoff:  minRetryBackoff,
+		},
+		{
+			name:         "backoff doubles",
+			channelID:    "direct",
+			attempts:     2,
+			publishErr:   errors.New("kafka is down"),
+			wantStatus:   chat_models.PendingScheduledStatus,
+			wantAttempts: 3,
+			wantBackoff:  4 * minRetryBackoff,
+		},
+		{
+			name:         "last attempt fails the message",
+			channelID:    "direct",
+			attempts:     maxAttempts - 1,
+			publishErr:   errors.New("kafka is down"),
+			wantStatus:   chat_models.FailedScheduledStatus,
+			wantAttempts: maxAttempts,
+			wantBackoff:  16 * minRetryBackoff,
+		},
+		{
+			name:         "removed channel fails at once",
+			channelID:    "removed",
+			wantStatus:   chat_models.FailedScheduledStatus,
+			wantAttempts: 1,
+			wantBackoff:  minRetryBackoff,
+			wantErr:      custom_errors.ErrNotFound,
+		},
+		{
+			name:      "blocked peer fails at once",
+			channelID: "direct",
+			userService: &fakeUserService{res: &pb.GetBlockedUsersResponse{
+				BlockedByUserIds: []string{"bob"},
+			}},
+			wantStatus:   chat_models.FailedScheduledStatus,
+			wantAttempts: 1,
+			wantBackoff:  minRetryBackoff,
+			wantErr:      custom_errors.ErrUserBlocked,
+		},
+		{
+			name:         "unavailable user service is retried",
+			channelID:    "direct",
+			userService:  &fakeUserService{err: errors.New("unavailable")},
+			wantStatus:   chat_models.PendingScheduledStatus,
+			wantAttempts: 1,
+			wantBackoff:  minRetryBackoff,
+			wantErr:      custom_errors.ErrBlockedUsersUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := newScheduledMessage(tt.channelID, time.Now().Unix()-1, tt.attempts)
+			scheduledRepo := &fakeScheduledRepo{msgs: []*chat_models.ScheduledMessage{sm}}
+			userService := tt.userService
+			if userService == nil {
+				userService = &fakeUserService{}
+			}
+			s := NewSchedulerService(scheduledRepo, &fakeMsgRepo{}, &fakeMsgPubRepo{err: tt.publishErr},
+				&fakeChannelRepo{channels: newTestChannels()}, userService, fakeTxManager{}, maxAttempts, zerolog.Nop())
+
+			before := time.Now().Unix()
+			s.DispatchDue(context.Background(), 1)
+			after := time.Now().Unix()
+
+			if sm.Status != tt.wantStatus || sm.Attempts != tt.wantAttempts {
+				t.Fatalf("got status %s after %d attempts, want %s after %d", sm.Status, sm.Attempts, tt.wantStatus, tt.wantAttempts)
+			}
+			backoff := int64(tt.wantBackoff.Seconds())
+			if sm.NextAttemptAt < before+backoff || sm.NextAttemptAt > after+backoff {
+				t.Fatalf("got next attempt at %d, want %d after now", sm.NextAttemptAt, backoff)
+			}
+			if tt.wantErr != nil && sm.LastError != tt.wantErr.Error() {
+				t.Fatalf("got last error %q, want %q", sm.LastError, tt.wantErr.Error())
+			}
+		})
+	}
+}
+
+func TestDispatchExcludesMembersWhoBlockedAuthor(t *testing.T) {
+	sm := newScheduledMessage("group", time.Now().Unix()-1, 0)
+	scheduledRepo := &fakeScheduledRepo{msgs: []*chat_models.ScheduledMessage{sm}}
+	msgPubRepo := &fakeMsgPubRepo{}
+	userService := &fakeUserService{res: &pb.GetBlockedUsersResponse{
+		BlockedByUserIds: []string{"carol"},
+	}}
+	s := NewSchedulerService(scheduledRepo, &fakeMsgRepo{}, msgPubRepo, &fakeChannelRepo{channels: newTestChannels()},
+		userService, fakeTxManager{}, 5, zerolog.Nop())
+
+	s.DispatchDue(context.Background(), 1)
+
+	if sm.Status != chat_models.SentScheduledStatus {
+		t.Fatalf("got status %s, want sent", sm.Status)
+	}
+	if len(msgPubRepo.published) != 1 {
+		t.Fatalf("got %d published, want 1", len(msgPubRepo.published))
+	}
+	receivers := make([]string, 0)
+	for userID := range msgPubRepo.published[0].ReceiverIDs {
+		receivers = append(receivers, userID)
+	}
+	slices.Sort(receivers)
+	if !slices.Equal(receivers, []string{"alice", "bob"}) {
+		t.Fatalf("got receivers %v, want alice and bob", receivers)
+	}
+}
@@ -0,0 +1,118 @@
+package structurization
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+	structurizationmodels "github.com/Petr09Mitin/xrust-beze-back/internal/models/structurization"
+	"github.com/Petr09Mitin/xrust-beze-back/internal/pkg/config"
+	channelrepo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/channel"
+	message_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/chat"
+	structurization_repo "github.com/Petr09Mitin/xrust-beze-back/internal/repository/structurization"
+	"github.com/rs/zerolog"
+)
+
+type StructurizationService interface {
+	ProcessStructurizationTask(ctx context.Context, task *chat_models.StructurizationTask) (*chat_models.Message, error)
+	ProcessSummaryTask(ctx context.Context, task *chat_models.SummaryTask) ([]chat_models.Message, error)
+}
+
+type StructurizationServiceImpl struct {
+	structurizationRepo      structurization_repo.StructurizationRepository
+	structurizationCacheRepo structurization_repo.StructurizationCacheRepo
+	messagesRepo             message_repo.MessageRepo
+	channelRepo              channelrepo.ChannelRepository
+	logger                   zerolog.Logger
+	cfg                      *config.StructurizationD
+}
+
+func NewStructurizationService(structurizationRepo structurization_repo.StructurizationRepository, structurizationCacheRepo structurization_repo.StructurizationCacheRepo, messagesRepo message_repo.MessageRepo, channelRepo channelrepo.ChannelRepository, logger zerolog.Logger, cfg *config.StructurizationD) StructurizationService {
+	return &StructurizationServiceImpl{
+		structurizationRepo:      structurizationRepo,
+		structurizationCacheRepo: structurizationCacheRepo,
+		messagesRepo:             messagesRepo,
+		channelRepo:              channelRepo,
+		logger:                   logger,
+		cfg:                      cfg,
+	}
+}
+
+// ProcessStructurizationTask saves the explanation and returns the update for channel members.
+// If structurization failed, the update has failed status and the error is returned too
+func (s *StructurizationServiceImpl) ProcessStructurizationTask(ctx context.Context, task *chat_models.StructurizationTask) (*chat_models.Message, error) {
+	defer func() {
+		// the mark is removed on any outcome, so the user can request the explanation again
+		if err := s.structurizationCacheRepo.FinishInFlight(context.WithoutCancel(ctx), task.InFlightKey()); err != nil {
+			s.logger.Error().Err(err).Str("message_id", task.Message.MessageID).Msg("failed to finish in-flight structurization")
+		}
+	}()
+	if task.Answer == "" {
+		failedMsg := s.newUpdate(task, task.Message, chat_models.StructurizationFailedStatus)
+		return &failedMsg, custom_errors.ErrCannotStructurizeEmptyAnswer
+	}
+	structurized, err := s.trySendStructurizationRequest(ctx, task.Question, task.Answer)
+	if err != nil {
+		s.logger.Error().Err(err).Str("message_id", task.Message.MessageID).Msg("failed to structurize message")
+		failedMsg := s.newUpdate(task, task.Message, chat_models.StructurizationFailedStatus)
+		return &failedMsg, err
+	}
+	if task.Hash != "" {
+		err = s.structurizationCacheRepo.Save(ctx, task.Hash, structurized)
+		if err != nil {
+			// the explanation is still saved to the message
+			s.logger.Error().Err(err).Str("message_id", task.Message.MessageID).Msg("failed to cache structurization")
+		}
+	}
+	now := time.Now().Unix()
+	updated, err := s.messagesRepo.SetStructurized(ctx, task.Message.MessageID, task.Message.NewStructurizedVersions(structurized, task.Hash, now), now)
+	if err != nil {
+		s.logger.Error().Err(err).Str("message_id", task.Message.MessageID).Msg("failed to save structurized message")
+		failedMsg := s.newUpdate(task, task.Message, chat_models.StructurizationFailedStatus)
+		return &failedMsg, err
+	}
+	newMsg := s.newUpdate(task, *updated, chat_models.StructurizationDoneStatus)
+	return &newMsg, nil
+}
+
+func (s *StructurizationServiceImpl) newUpdate(task *chat_models.StructurizationTask, msg chat_models.Message, status string) chat_models.Message {
+	msg.ReceiverIDs = task.Message.ReceiverIDs
+	msg.Type = chat_models.UpdateMessageType
+	msg.Event = chat_models.StructurizationEvent
+	msg.Status = status
+	return msg
+}
+
+func (s *StructurizationServiceImpl) trySendStructurizationRequest(ctx context.Context, question, answer string) (string, error) {
+	return s.trySendRequest(ctx, func(ctx context.Context) (*structurizationmodels.StructurizedMessage, error) {
+		return s.structurizationRepo.SendStructRequest(ctx, question, answer)
+	})
+}
+
+func (s *StructurizationServiceImpl) trySendRequest(ctx context.Context, send func(ctx context.Context) (*structurizationmodels.StructurizedMessage, error)) (string, error) {
+	newCtx, cancel := context.WithTimeout(
+		ctx,
+		time.Duration(s.cfg.Services.StructurizationService.Timeout)*time.Second,
+	)
+	defer cancel()
+	i := s.cfg.Services.StructurizationService.MaxRetries
+loop:
+	for i > 0 {
+		select {
+		case <-newCtx.Done():
+			return "", custom_errors.ErrRequestTimeout
+		default:
+			i--
+			structurized, err := send(newCtx)
+			if err != nil {
+				s.logger.Error().Err(err).Msg(fmt.Sprintf("trySendRequest failed, %d retries remaining", i))
+				continue loop
+			}
+			return structurized.Explanation, nil
+		}
+	}
+
+	return "", custom_errors.ErrMaxRetriesExceeded
+}
@@ -0,0 +1,126 @@
+package structurization
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	chat_models "github.com/Petr09Mitin/xrust-beze-back/internal/models/chat"
+	custom_errors "github.com/Petr09Mitin/xrust-beze-back/internal/models/error"
+	structurizationmodels "github.com/Petr09Mitin/xrust-beze-back/internal/models/structurization"
+)
+
+const (
+	// the latest messages of the window are summarized, older ones are dropped
+	maxSummaryMessages   = 500
+	transcriptTimeLayout = "2006-01-02 15:04"
+)
+
+// ProcessSummaryTask summarizes the window, saves the summary as a pinned message of the requester
+// and returns it with the pin update for channel members. If summary failed, the update with failed status
+// is returned for the requester. If only pinning failed, the summary is returned with the pin failure for the requester
+func (s *StructurizationServiceImpl) ProcessSummaryTask(ctx context.Context, task *chat_models.SummaryTask) ([]chat_models.Message, error) {
+	msgs, err := s.messagesRepo.GetMessagesInWindow(ctx, task.ChannelID, task.From, task.To, maxSummaryMessages)
+	if err != nil {
+		s.logger.Error().Err(err).Str("channel_id", task.ChannelID).Msg("failed to get messages to summarize")
+		return s.newSummaryFailedUpdate(task), err
+	}
+	transcript := s.buildTranscript(msgs, task.Usernames)
+	if transcript == "" {
+		return s.newSummaryFailedUpdate(task), custom_errors.ErrNothingToSummarize
+	}
+	summary, err := s.trySendRequest(ctx, func(ctx context.Context) (*structurizationmodels.StructurizedMessage, error) {
+		return s.structurizationRepo.SendSummaryRequest(ctx, transcript)
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Str("channel_id", task.ChannelID).Msg("failed to summarize channel")
+		return s.newSummaryFailedUpdate(task), err
+	}
+
+	now := time.Now().Unix()
+	inserted, err := s.messagesRepo.InsertMessage(ctx, chat_models.Message{
+		ChannelID:   task.ChannelID,
+		UserID:      task.UserID,
+		Payload:     summary,
+		Attachments: make([]chat_models.Attachment, 0),
+		Summary: &chat_models.SummaryInfo{
+			From:          task.From,
+			To:            task.To,
+			MessagesCount: len(msgs),
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Str("channel_id", task.ChannelID).Msg("failed to save summary")
+		return s.newSummaryFailedUpdate(task), err
+	}
+	inserted.Event = chat_models.SummaryEvent
+	inserted.Type = chat_models.SendMessageType
+	inserted.SetReceiverIDs(task.ReceiverIDs)
+
+	channel, err := s.channelRepo.PinMessage(ctx, task.ChannelID, inserted.MessageID, chat_models.MaxPinnedMessages)
+	if err != nil {
+		// summary is still available in the history
+		s.logger.Error().Err(err).Str("channel_id", task.ChannelID).Msg("failed to pin summary")
+		pinFailed := chat_models.Message{
+			MessageID: inserted.MessageID,
+			Event:     chat_models.SummaryEvent,
+			Type:      chat_models.UpdateMessageType,
+			ChannelID: task.ChannelID,
+			UserID:    task.UserID,
+			Status:    chat_models.SummaryPinFailedStatus,
+		}
+		pinFailed.SetReceiverIDs([]string{task.UserID})
+		return []chat_models.Message{inserted, pinFailed}, err
+	}
+	pinEvent := chat_models.Message{
+		MessageID:        inserted.MessageID,
+		Event:            chat_models.PinEvent,
+		Type:             chat_models.SendMessageType,
+		ChannelID:        task.ChannelID,
+		UserID:           task.UserID,
+		PinnedMessageIDs: channel.PinnedMessageIDs,
+	}
+	pinEvent.SetReceiverIDs(task.ReceiverIDs)
+	return []chat_models.Message{inserted, pinEvent}, nil
+}
+
+func (s *StructurizationServiceImpl) newSummaryFailedUpdate(task *chat_models.SummaryTask) []chat_models.Message {
+	msg := chat_models.Message{
+		Event:     chat_models.SummaryEvent,
+		Type:      chat_models.SendMessageType,
+		ChannelID: task.ChannelID,
+		UserID:    task.UserID,
+		Status:    chat_models.SummaryFailedStatus,
+	}
+	msg.SetReceiverIDs([]string{task.UserID})
+	return []chat_models.Message{msg}
+}
+
+// buildTranscript makes one line per message, recognized voice is used as the text of voice messages
+func (s *StructurizationServiceImpl) buildTranscript(msgs []chat_models.Message, usernames map[string]string) string {
+	var sb strings.Builder
+	for _, msg := range msgs {
+		if msg.Summary != nil {
+			continue
+		}
+		text := msg.Payload
+		if msg.RecognizedVoice != "" {
+			text = strings.TrimSpace(text + " " + msg.RecognizedVoice)
+		}
+		if len(msg.Attachments) > 0 {
+			text = strings.TrimSpace(fmt.Sprintf("%s [attachments: %d]", text, len(msg.Attachments)))
+		}
+		if text == "" {
+			continue
+		}
+		author, ok := usernames[msg.UserID]
+		if !ok {
+			author = msg.UserID
+		}
+		sb.WriteString(fmt.Sprintf("[%s] %s: %s\n", time.Unix(msg.CreatedAt, 0).UTC().Format(transcriptTimeLayout), author, text))
+	}
+	return sb.String()
+}
@@ -28,6 +28,7 @@ type UserService interface {
 	GetByUsername(ctx context.Context, username string) (*user_model.User, error)
 	GetByUsernameWithPassword(ctx context.Context, username string) (*auth_model.RegisterRequest, error)
 	Update(ctx context.Context, user *user_model.User) error
+	UpdateLastActiveAt(ctx context.Context, id string, lastActiveAt time.Time) error
 	Delete(ctx context.Context, id string) error
 	List(ctx context.Context, page, limit int) ([]*user_model.User, error)
 	FindMatchingUsers(ctx context.Context, userID string) ([]*user_model.User, error)
@@ -37,6 +38,10 @@ type UserService interface {
 	DeleteReview(ctx context.Context, userID string, reviewID string) error
 	FindBySkillsToShare(ctx context.Context, skills []string, limit, offset int64) ([]*user_model.User, error)
 	FindBySkillsToLearn(ctx context.Context, skills []string, limit, offset int64) ([]*user_model.User, error)
+	BlockUser(ctx context.Context, userID, blockedUserID string) error
+	UnblockUser(ctx context.Context, userID, blockedUserID string) error
+	GetBlockedUsers(ctx context.Context, userID string) ([]*user_model.User, error)
+	GetBlockRelations(ctx context.Context, userID string) (blockedUserIDs, blockedByUserIDs []string, err error)
 }
 
 type userService struct {
@@ -188,6 +193,13 @@ func (s *userService) Update(ctx context.Context, user *user_model.User) error {
 	return nil
 }
 
+func (s *userService) UpdateLastActiveAt(ctx context.Context, id string, lastActiveAt time.Time) error {
+	if lastActiveAt.IsZero() {
+		lastActiveAt = time.Now()
+	}
+	return s.userRepo.UpdateLastActiveAt(ctx, id, lastActiveAt)
+}
+
 func (s *userService) Delete(ctx context.Context, id string) error {
 	// Проверяем существование пользователя
 	user, err := s.userRepo.GetByID(ctx, id)
@@ -244,9 +256,22 @@ func (s *userService) FindMatchingUsers(ctx context.Context, userID string) ([]*
 		return nil, err
 	}
 
+	// Заблокированные в любую сторону пользователи не предлагаются
+	blockedByUserIDs, err := s.userRepo.GetBlockedByUserIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	excludedIDs := make(map[string]struct{}, len(currentUser.BlockedUserIDs)+len(blockedByUserIDs))
+	for _, id := range append(blockedByUserIDs, currentUser.BlockedUserIDs...) {
+		excludedIDs[id] = struct{}{}
+	}
+
 	// Фильтруем текущего пользователя из результатов
 	filteredUsers := make([]*user_model.User, 0)
 	for _, u := range matchingUsers {
+		if _, excluded := excludedIDs[u.ID.Hex()]; excluded {
+			continue
+		}
 		if u.ID != currentUser.ID {
 			u.Avatar = defaults.ApplyDefaultIfEmptyAvatar(u.Avatar)
 			filteredUsers = append(filteredUsers, u)
@@ -462,3 +487,49 @@ func (s *userService) FindBySkillsToLearn(ctx context.Context, skills []string,
 	}
 	return users, nil
 }
+
+func (s *userService) BlockUser(ctx context.Context, userID, blockedUserID string) error {
+	if userID == blockedUserID {
+		return custom_errors.ErrCanNotSelfBlock
+	}
+	_, err := s.userRepo.GetByID(ctx, blockedUserID)
+	if err != nil {
+		return custom_errors.ErrUserNotExists
+	}
+	return s.userRepo.Block(ctx, userID, blockedUserID)
+}
+
+func (s *userService) UnblockUser(ctx context.Context, userID, blockedUserID string) error {
+	return s.userRepo.Unblock(ctx, userID, blockedUserID)
+}
+
+func (s *userService) GetBlockedUsers(ctx context.Context, userID string) ([]*user_model.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(user.BlockedUserIDs) == 0 {
+		return make([]*user_model.User, 0), nil
+	}
+	users, err := s.userRepo.GetByIDs(ctx, user.BlockedUserIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		u.Avatar = defaults.ApplyDefaultIfEmptyAvatar(u.Avatar)
+	}
+	return users, nil
+}
+
+// GetBlockRelations returns users blocked by the user and users who have blocked the user
+func (s *userService) GetBlockRelations(ctx context.Context, userID string) ([]string, []string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	blockedByUserIDs, err := s.userRepo.GetBlockedByUserIDs(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user.BlockedUserIDs, blockedByUserIDs, nil
+}
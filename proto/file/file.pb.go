@@ -401,16 +401,101 @@ func (x *MoveTempFilesToAttachmentsRequest) GetFilenames() []string {
 	return nil
 }
 
+type AttachmentMetadata struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	OriginalName  string                 `protobuf:"bytes,2,opt,name=original_name,json=originalName,proto3" json:"original_name,omitempty"`
+	Size          int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	MimeType      string                 `protobuf:"bytes,4,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
+	Width         int32                  `protobuf:"varint,5,opt,name=width,proto3" json:"width,omitempty"`
+	Height        int32                  `protobuf:"varint,6,opt,name=height,proto3" json:"height,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttachmentMetadata) Reset() {
+	*x = AttachmentMetadata{}
+	mi := &file_proto_file_file_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttachmentMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachmentMetadata) ProtoMessage() {}
+
+func (x *AttachmentMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_file_file_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachmentMetadata.ProtoReflect.Descriptor instead.
+func (*AttachmentMetadata) Descriptor() ([]byte, []int) {
+	return file_proto_file_file_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AttachmentMetadata) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *AttachmentMetadata) GetOriginalName() string {
+	if x != nil {
+		return x.OriginalName
+	}
+	return ""
+}
+
+func (x *AttachmentMetadata) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *AttachmentMetadata) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *AttachmentMetadata) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *AttachmentMetadata) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
 type MoveTempFilesToAttachmentsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Filenames     []string               `protobuf:"bytes,1,rep,name=filenames,proto3" json:"filenames,omitempty"`
+	Attachments   []*AttachmentMetadata  `protobuf:"bytes,2,rep,name=attachments,proto3" json:"attachments,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *MoveTempFilesToAttachmentsResponse) Reset() {
 	*x = MoveTempFilesToAttachmentsResponse{}
-	mi := &file_proto_file_file_proto_msgTypes[9]
+	mi := &file_proto_file_file_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -422,7 +507,7 @@ func (x *MoveTempFilesToAttachmentsResponse) String() string {
 func (*MoveTempFilesToAttachmentsResponse) ProtoMessage() {}
 
 func (x *MoveTempFilesToAttachmentsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_file_file_proto_msgTypes[9]
+	mi := &file_proto_file_file_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -435,7 +520,7 @@ func (x *MoveTempFilesToAttachmentsResponse) ProtoReflect() protoreflect.Message
 
 // Deprecated: Use MoveTempFilesToAttachmentsResponse.ProtoReflect.Descriptor instead.
 func (*MoveTempFilesToAttachmentsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_file_file_proto_rawDescGZIP(), []int{9}
+	return file_proto_file_file_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *MoveTempFilesToAttachmentsResponse) GetFilenames() []string {
@@ -445,6 +530,13 @@ func (x *MoveTempFilesToAttachmentsResponse) GetFilenames() []string {
 	return nil
 }
 
+func (x *MoveTempFilesToAttachmentsResponse) GetAttachments() []*AttachmentMetadata {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
 type DeleteAttachmentsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Filenames     []string               `protobuf:"bytes,1,rep,name=filenames,proto3" json:"filenames,omitempty"`
@@ -454,7 +546,7 @@ type DeleteAttachmentsRequest struct {
 
 func (x *DeleteAttachmentsRequest) Reset() {
 	*x = DeleteAttachmentsRequest{}
-	mi := &file_proto_file_file_proto_msgTypes[10]
+	mi := &file_proto_file_file_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -466,7 +558,7 @@ func (x *DeleteAttachmentsRequest) String() string {
 func (*DeleteAttachmentsRequest) ProtoMessage() {}
 
 func (x *DeleteAttachmentsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_file_file_proto_msgTypes[10]
+	mi := &file_proto_file_file_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -479,7 +571,7 @@ func (x *DeleteAttachmentsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteAttachmentsRequest.ProtoReflect.Descriptor instead.
 func (*DeleteAttachmentsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_file_file_proto_rawDescGZIP(), []int{10}
+	return file_proto_file_file_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *DeleteAttachmentsRequest) GetFilenames() []string {
@@ -497,7 +589,7 @@ type DeleteAttachmentsResponse struct {
 
 func (x *DeleteAttachmentsResponse) Reset() {
 	*x = DeleteAttachmentsResponse{}
-	mi := &file_proto_file_file_proto_msgTypes[11]
+	mi := &file_proto_file_file_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -509,7 +601,7 @@ func (x *DeleteAttachmentsResponse) String() string {
 func (*DeleteAttachmentsResponse) ProtoMessage() {}
 
 func (x *DeleteAttachmentsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_file_file_proto_msgTypes[11]
+	mi := &file_proto_file_file_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -522,7 +614,7 @@ func (x *DeleteAttachmentsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteAttachmentsResponse.ProtoReflect.Descriptor instead.
 func (*DeleteAttachmentsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_file_file_proto_rawDescGZIP(), []int{11}
+	return file_proto_file_file_proto_rawDescGZIP(), []int{12}
 }
 
 type CopyAttachmentToStudyMaterialsRequest struct {
@@ -534,7 +626,7 @@ type CopyAttachmentToStudyMaterialsRequest struct {
 
 func (x *CopyAttachmentToStudyMaterialsRequest) Reset() {
 	*x = CopyAttachmentToStudyMaterialsRequest{}
-	mi := &file_proto_file_file_proto_msgTypes[12]
+	mi := &file_proto_file_file_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -546,7 +638,7 @@ func (x *CopyAttachmentToStudyMaterialsRequest) String() string {
 func (*CopyAttachmentToStudyMaterialsRequest) ProtoMessage() {}
 
 func (x *CopyAttachmentToStudyMaterialsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_file_file_proto_msgTypes[12]
+	mi := &file_proto_file_file_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -559,7 +651,7 @@ func (x *CopyAttachmentToStudyMaterialsRequest) ProtoReflect() protoreflect.Mess
 
 // Deprecated: Use CopyAttachmentToStudyMaterialsRequest.ProtoReflect.Descriptor instead.
 func (*CopyAttachmentToStudyMaterialsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_file_file_proto_rawDescGZIP(), []int{12}
+	return file_proto_file_file_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *CopyAttachmentToStudyMaterialsRequest) GetFilename() string {
@@ -578,7 +670,7 @@ type CopyAttachmentToStudyMaterialsResponse struct {
 
 func (x *CopyAttachmentToStudyMaterialsResponse) Reset() {
 	*x = CopyAttachmentToStudyMaterialsResponse{}
-	mi := &file_proto_file_file_proto_msgTypes[13]
+	mi := &file_proto_file_file_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -590,7 +682,7 @@ func (x *CopyAttachmentToStudyMaterialsResponse) String() string {
 func (*CopyAttachmentToStudyMaterialsResponse) ProtoMessage() {}
 
 func (x *CopyAttachmentToStudyMaterialsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_file_file_proto_msgTypes[13]
+	mi := &file_proto_file_file_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -603,7 +695,7 @@ func (x *CopyAttachmentToStudyMaterialsResponse) ProtoReflect() protoreflect.Mes
 
 // Deprecated: Use CopyAttachmentToStudyMaterialsResponse.ProtoReflect.Descriptor instead.
 func (*CopyAttachmentToStudyMaterialsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_file_file_proto_rawDescGZIP(), []int{13}
+	return file_proto_file_file_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *CopyAttachmentToStudyMaterialsResponse) GetFilename() string {
@@ -613,6 +705,94 @@ func (x *CopyAttachmentToStudyMaterialsResponse) GetFilename() string {
 	return ""
 }
 
+type CopyAttachmentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filenames     []string               `protobuf:"bytes,1,rep,name=filenames,proto3" json:"filenames,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CopyAttachmentsRequest) Reset() {
+	*x = CopyAttachmentsRequest{}
+	mi := &file_proto_file_file_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CopyAttachmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CopyAttachmentsRequest) ProtoMessage() {}
+
+func (x *CopyAttachmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_file_file_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CopyAttachmentsRequest.ProtoReflect.Descriptor instead.
+func (*CopyAttachmentsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_file_file_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CopyAttachmentsRequest) GetFilenames() []string {
+	if x != nil {
+		return x.Filenames
+	}
+	return nil
+}
+
+type CopyAttachmentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filenames     []string               `protobuf:"bytes,1,rep,name=filenames,proto3" json:"filenames,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CopyAttachmentsResponse) Reset() {
+	*x = CopyAttachmentsResponse{}
+	mi := &file_proto_file_file_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CopyAttachmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CopyAttachmentsResponse) ProtoMessage() {}
+
+func (x *CopyAttachmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_file_file_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CopyAttachmentsResponse.ProtoReflect.Descriptor instead.
+func (*CopyAttachmentsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_file_file_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CopyAttachmentsResponse) GetFilenames() []string {
+	if x != nil {
+		return x.Filenames
+	}
+	return nil
+}
+
 var File_proto_file_file_proto protoreflect.FileDescriptor
 
 const file_proto_file_file_proto_rawDesc = "" +
@@ -633,16 +813,28 @@ const file_proto_file_file_proto_rawDesc = "" +
 	"\bfilename\x18\x01 \x01(\tR\bfilename\"\x1c\n" +
 	"\x1aDeleteVoiceMessageResponse\"A\n" +
 	"!MoveTempFilesToAttachmentsRequest\x12\x1c\n" +
-	"\tfilenames\x18\x01 \x03(\tR\tfilenames\"B\n" +
+	"\tfilenames\x18\x01 \x03(\tR\tfilenames\"\xb4\x01\n" +
+	"\x12AttachmentMetadata\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12#\n" +
+	"\roriginal_name\x18\x02 \x01(\tR\foriginalName\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\x12\x1b\n" +
+	"\tmime_type\x18\x04 \x01(\tR\bmimeType\x12\x14\n" +
+	"\x05width\x18\x05 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\x06 \x01(\x05R\x06height\"~\n" +
 	"\"MoveTempFilesToAttachmentsResponse\x12\x1c\n" +
-	"\tfilenames\x18\x01 \x03(\tR\tfilenames\"8\n" +
+	"\tfilenames\x18\x01 \x03(\tR\tfilenames\x12:\n" +
+	"\vattachments\x18\x02 \x03(\v2\x18.file.AttachmentMetadataR\vattachments\"8\n" +
 	"\x18DeleteAttachmentsRequest\x12\x1c\n" +
 	"\tfilenames\x18\x01 \x03(\tR\tfilenames\"\x1b\n" +
 	"\x19DeleteAttachmentsResponse\"C\n" +
 	"%CopyAttachmentToStudyMaterialsRequest\x12\x1a\n" +
 	"\bfilename\x18\x01 \x01(\tR\bfilename\"D\n" +
 	"&CopyAttachmentToStudyMaterialsResponse\x12\x1a\n" +
-	"\bfilename\x18\x01 \x01(\tR\bfilename2\xc7\x05\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\"6\n" +
+	"\x16CopyAttachmentsRequest\x12\x1c\n" +
+	"\tfilenames\x18\x01 \x03(\tR\tfilenames\"7\n" +
+	"\x17CopyAttachmentsResponse\x12\x1c\n" +
+	"\tfilenames\x18\x01 \x03(\tR\tfilenames2\x97\x06\n" +
 	"\vFileService\x12`\n" +
 	"\x15MoveTempFileToAvatars\x12\".file.MoveTempFileToAvatarsRequest\x1a#.file.MoveTempFileToAvatarsResponse\x12E\n" +
 	"\fDeleteAvatar\x12\x19.file.DeleteAvatarRequest\x1a\x1a.file.DeleteAvatarResponse\x12r\n" +
@@ -650,7 +842,8 @@ const file_proto_file_file_proto_rawDesc = "" +
 	"\x12DeleteVoiceMessage\x12\x1f.file.DeleteVoiceMessageRequest\x1a .file.DeleteVoiceMessageResponse\x12o\n" +
 	"\x1aMoveTempFilesToAttachments\x12'.file.MoveTempFilesToAttachmentsRequest\x1a(.file.MoveTempFilesToAttachmentsResponse\x12T\n" +
 	"\x11DeleteAttachments\x12\x1e.file.DeleteAttachmentsRequest\x1a\x1f.file.DeleteAttachmentsResponse\x12{\n" +
-	"\x1eCopyAttachmentToStudyMaterials\x12+.file.CopyAttachmentToStudyMaterialsRequest\x1a,.file.CopyAttachmentToStudyMaterialsResponseB\fZ\n" +
+	"\x1eCopyAttachmentToStudyMaterials\x12+.file.CopyAttachmentToStudyMaterialsRequest\x1a,.file.CopyAttachmentToStudyMaterialsResponse\x12N\n" +
+	"\x0fCopyAttachments\x12\x1c.file.CopyAttachmentsRequest\x1a\x1d.file.CopyAttachmentsResponseB\fZ\n" +
 	"proto/fileb\x06proto3"
 
 var (
@@ -665,7 +858,7 @@ func file_proto_file_file_proto_rawDescGZIP() []byte {
 	return file_proto_file_file_proto_rawDescData
 }
 
-var file_proto_file_file_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_proto_file_file_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
 var file_proto_file_file_proto_goTypes = []any{
 	(*MoveTempFileToAvatarsRequest)(nil),           // 0: file.MoveTempFileToAvatarsRequest
 	(*MoveTempFileToAvatarsResponse)(nil),          // 1: file.MoveTempFileToAvatarsResponse
@@ -676,32 +869,38 @@ var file_proto_file_file_proto_goTypes = []any{
 	(*DeleteVoiceMessageRequest)(nil),              // 6: file.DeleteVoiceMessageRequest
 	(*DeleteVoiceMessageResponse)(nil),             // 7: file.DeleteVoiceMessageResponse
 	(*MoveTempFilesToAttachmentsRequest)(nil),      // 8: file.MoveTempFilesToAttachmentsRequest
-	(*MoveTempFilesToAttachmentsResponse)(nil),     // 9: file.MoveTempFilesToAttachmentsResponse
-	(*DeleteAttachmentsRequest)(nil),               // 10: file.DeleteAttachmentsRequest
-	(*DeleteAttachmentsResponse)(nil),              // 11: file.DeleteAttachmentsResponse
-	(*CopyAttachmentToStudyMaterialsRequest)(nil),  // 12: file.CopyAttachmentToStudyMaterialsRequest
-	(*CopyAttachmentToStudyMaterialsResponse)(nil), // 13: file.CopyAttachmentToStudyMaterialsResponse
+	(*AttachmentMetadata)(nil),                     // 9: file.AttachmentMetadata
+	(*MoveTempFilesToAttachmentsResponse)(nil),     // 10: file.MoveTempFilesToAttachmentsResponse
+	(*DeleteAttachmentsRequest)(nil),               // 11: file.DeleteAttachmentsRequest
+	(*DeleteAttachmentsResponse)(nil),              // 12: file.DeleteAttachmentsResponse
+	(*CopyAttachmentToStudyMaterialsRequest)(nil),  // 13: file.CopyAttachmentToStudyMaterialsRequest
+	(*CopyAttachmentToStudyMaterialsResponse)(nil), // 14: file.CopyAttachmentToStudyMaterialsResponse
+	(*CopyAttachmentsRequest)(nil),                 // 15: file.CopyAttachmentsRequest
+	(*CopyAttachmentsResponse)(nil),                // 16: file.CopyAttachmentsResponse
 }
 var file_proto_file_file_proto_depIdxs = []int32{
-	0,  // 0: file.FileService.MoveTempFileToAvatars:input_type -> file.MoveTempFileToAvatarsRequest
-	2,  // 1: file.FileService.DeleteAvatar:input_type -> file.DeleteAvatarRequest
-	4,  // 2: file.FileService.MoveTempFileToVoiceMessages:input_type -> file.MoveTempFileToVoiceMessagesRequest
-	6,  // 3: file.FileService.DeleteVoiceMessage:input_type -> file.DeleteVoiceMessageRequest
-	8,  // 4: file.FileService.MoveTempFilesToAttachments:input_type -> file.MoveTempFilesToAttachmentsRequest
-	10, // 5: file.FileService.DeleteAttachments:input_type -> file.DeleteAttachmentsRequest
-	12, // 6: file.FileService.CopyAttachmentToStudyMaterials:input_type -> file.CopyAttachmentToStudyMaterialsRequest
-	1,  // 7: file.FileService.MoveTempFileToAvatars:output_type -> file.MoveTempFileToAvatarsResponse
-	3,  // 8: file.FileService.DeleteAvatar:output_type -> file.DeleteAvatarResponse
-	5,  // 9: file.FileService.MoveTempFileToVoiceMessages:output_type -> file.MoveTempFileToVoiceMessagesResponse
-	7,  // 10: file.FileService.DeleteVoiceMessage:output_type -> file.DeleteVoiceMessageResponse
-	9,  // 11: file.FileService.MoveTempFilesToAttachments:output_type -> file.MoveTempFilesToAttachmentsResponse
-	11, // 12: file.FileService.DeleteAttachments:output_type -> file.DeleteAttachmentsResponse
-	13, // 13: file.FileService.CopyAttachmentToStudyMaterials:output_type -> file.CopyAttachmentToStudyMaterialsResponse
-	7,  // [7:14] is the sub-list for method output_type
-	0,  // [0:7] is the sub-list for method input_type
-	0,  // [0:0] is the sub-list for extension type_name
-	0,  // [0:0] is the sub-list for extension extendee
-	0,  // [0:0] is the sub-list for field type_name
+	9,  // 0: file.MoveTempFilesToAttachmentsResponse.attachments:type_name -> file.AttachmentMetadata
+	0,  // 1: file.FileService.MoveTempFileToAvatars:input_type -> file.MoveTempFileToAvatarsRequest
+	2,  // 2: file.FileService.DeleteAvatar:input_type -> file.DeleteAvatarRequest
+	4,  // 3: file.FileService.MoveTempFileToVoiceMessages:input_type -> file.MoveTempFileToVoiceMessagesRequest
+	6,  // 4: file.FileService.DeleteVoiceMessage:input_type -> file.DeleteVoiceMessageRequest
+	8,  // 5: file.FileService.MoveTempFilesToAttachments:input_type -> file.MoveTempFilesToAttachmentsRequest
+	11, // 6: file.FileService.DeleteAttachments:input_type -> file.DeleteAttachmentsRequest
+	13, // 7: file.FileService.CopyAttachmentToStudyMaterials:input_type -> file.CopyAttachmentToStudyMaterialsRequest
+	15, // 8: file.FileService.CopyAttachments:input_type -> file.CopyAttachmentsRequest
+	1,  // 9: file.FileService.MoveTempFileToAvatars:output_type -> file.MoveTempFileToAvatarsResponse
+	3,  // 10: file.FileService.DeleteAvatar:output_type -> file.DeleteAvatarResponse
+	5,  // 11: file.FileService.MoveTempFileToVoiceMessages:output_type -> file.MoveTempFileToVoiceMessagesResponse
+	7,  // 12: file.FileService.DeleteVoiceMessage:output_type -> file.DeleteVoiceMessageResponse
+	10, // 13: file.FileService.MoveTempFilesToAttachments:output_type -> file.MoveTempFilesToAttachmentsResponse
+	12, // 14: file.FileService.DeleteAttachments:output_type -> file.DeleteAttachmentsResponse
+	14, // 15: file.FileService.CopyAttachmentToStudyMaterials:output_type -> file.CopyAttachmentToStudyMaterialsResponse
+	16, // 16: file.FileService.CopyAttachments:output_type -> file.CopyAttachmentsResponse
+	9,  // [9:17] is the sub-list for method output_type
+	1,  // [1:9] is the sub-list for method input_type
+	1,  // [1:1] is the sub-list for extension type_name
+	1,  // [1:1] is the sub-list for extension extendee
+	0,  // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_proto_file_file_proto_init() }
@@ -715,7 +914,7 @@ func file_proto_file_file_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_file_file_proto_rawDesc), len(file_proto_file_file_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   14,
+			NumMessages:   17,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -26,6 +26,7 @@ const (
 	FileService_MoveTempFilesToAttachments_FullMethodName     = "/file.FileService/MoveTempFilesToAttachments"
 	FileService_DeleteAttachments_FullMethodName              = "/file.FileService/DeleteAttachments"
 	FileService_CopyAttachmentToStudyMaterials_FullMethodName = "/file.FileService/CopyAttachmentToStudyMaterials"
+	FileService_CopyAttachments_FullMethodName                = "/file.FileService/CopyAttachments"
 )
 
 // FileServiceClient is the client API for FileService service.
@@ -39,6 +40,7 @@ type FileServiceClient interface {
 	MoveTempFilesToAttachments(ctx context.Context, in *MoveTempFilesToAttachmentsRequest, opts ...grpc.CallOption) (*MoveTempFilesToAttachmentsResponse, error)
 	DeleteAttachments(ctx context.Context, in *DeleteAttachmentsRequest, opts ...grpc.CallOption) (*DeleteAttachmentsResponse, error)
 	CopyAttachmentToStudyMaterials(ctx context.Context, in *CopyAttachmentToStudyMaterialsRequest, opts ...grpc.CallOption) (*CopyAttachmentToStudyMaterialsResponse, error)
+	CopyAttachments(ctx context.Context, in *CopyAttachmentsRequest, opts ...grpc.CallOption) (*CopyAttachmentsResponse, error)
 }
 
 type fileServiceClient struct {
@@ -119,6 +121,16 @@ func (c *fileServiceClient) CopyAttachmentToStudyMaterials(ctx context.Context,
 	return out, nil
 }
 
+func (c *fileServiceClient) CopyAttachments(ctx context.Context, in *CopyAttachmentsRequest, opts ...grpc.CallOption) (*CopyAttachmentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CopyAttachmentsResponse)
+	err := c.cc.Invoke(ctx, FileService_CopyAttachments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // FileServiceServer is the server API for FileService service.
 // All implementations must embed UnimplementedFileServiceServer
 // for forward compatibility.
@@ -130,6 +142,7 @@ type FileServiceServer interface {
 	MoveTempFilesToAttachments(context.Context, *MoveTempFilesToAttachmentsRequest) (*MoveTempFilesToAttachmentsResponse, error)
 	DeleteAttachments(context.Context, *DeleteAttachmentsRequest) (*DeleteAttachmentsResponse, error)
 	CopyAttachmentToStudyMaterials(context.Context, *CopyAttachmentToStudyMaterialsRequest) (*CopyAttachmentToStudyMaterialsResponse, error)
+	CopyAttachments(context.Context, *CopyAttachmentsRequest) (*CopyAttachmentsResponse, error)
 	mustEmbedUnimplementedFileServiceServer()
 }
 
@@ -161,6 +174,9 @@ func (UnimplementedFileServiceServer) DeleteAttachments(context.Context, *Delete
 func (UnimplementedFileServiceServer) CopyAttachmentToStudyMaterials(context.Context, *CopyAttachmentToStudyMaterialsRequest) (*CopyAttachmentToStudyMaterialsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CopyAttachmentToStudyMaterials not implemented")
 }
+func (UnimplementedFileServiceServer) CopyAttachments(context.Context, *CopyAttachmentsRequest) (*CopyAttachmentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CopyAttachments not implemented")
+}
 func (UnimplementedFileServiceServer) mustEmbedUnimplementedFileServiceServer() {}
 func (UnimplementedFileServiceServer) testEmbeddedByValue()                     {}
 
@@ -308,6 +324,24 @@ func _FileService_CopyAttachmentToStudyMaterials_Handler(srv interface{}, ctx co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FileService_CopyAttachments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CopyAttachmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).CopyAttachments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileService_CopyAttachments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).CopyAttachments(ctx, req.(*CopyAttachmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // FileService_ServiceDesc is the grpc.ServiceDesc for FileService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -343,6 +377,10 @@ var FileService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CopyAttachmentToStudyMaterials",
 			Handler:    _FileService_CopyAttachmentToStudyMaterials_Handler,
 		},
+		{
+			MethodName: "CopyAttachments",
+			Handler:    _FileService_CopyAttachments_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/file/file.proto",
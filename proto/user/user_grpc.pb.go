@@ -28,6 +28,8 @@ const (
 	UserService_ListUsers_FullMethodName                = "/user.UserService/ListUsers"
 	UserService_FindMatchingUsers_FullMethodName        = "/user.UserService/FindMatchingUsers"
 	UserService_FindBySkillsToShare_FullMethodName      = "/user.UserService/FindBySkillsToShare"
+	UserService_UpdateLastActiveAt_FullMethodName       = "/user.UserService/UpdateLastActiveAt"
+	UserService_GetBlockedUsers_FullMethodName          = "/user.UserService/GetBlockedUsers"
 )
 
 // UserServiceClient is the client API for UserService service.
@@ -43,6 +45,8 @@ type UserServiceClient interface {
 	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
 	FindMatchingUsers(ctx context.Context, in *FindMatchingUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
 	FindBySkillsToShare(ctx context.Context, in *FindBySkillsToShareRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
+	UpdateLastActiveAt(ctx context.Context, in *UpdateLastActiveAtRequest, opts ...grpc.CallOption) (*UpdateLastActiveAtResponse, error)
+	GetBlockedUsers(ctx context.Context, in *GetBlockedUsersRequest, opts ...grpc.CallOption) (*GetBlockedUsersResponse, error)
 }
 
 type userServiceClient struct {
@@ -143,6 +147,26 @@ func (c *userServiceClient) FindBySkillsToShare(ctx context.Context, in *FindByS
 	return out, nil
 }
 
+func (c *userServiceClient) UpdateLastActiveAt(ctx context.Context, in *UpdateLastActiveAtRequest, opts ...grpc.CallOption) (*UpdateLastActiveAtResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateLastActiveAtResponse)
+	err := c.cc.Invoke(ctx, UserService_UpdateLastActiveAt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetBlockedUsers(ctx context.Context, in *GetBlockedUsersRequest, opts ...grpc.CallOption) (*GetBlockedUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBlockedUsersResponse)
+	err := c.cc.Invoke(ctx, UserService_GetBlockedUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility.
@@ -156,6 +180,8 @@ type UserServiceServer interface {
 	ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error)
 	FindMatchingUsers(context.Context, *FindMatchingUsersRequest) (*ListUsersResponse, error)
 	FindBySkillsToShare(context.Context, *FindBySkillsToShareRequest) (*ListUsersResponse, error)
+	UpdateLastActiveAt(context.Context, *UpdateLastActiveAtRequest) (*UpdateLastActiveAtResponse, error)
+	GetBlockedUsers(context.Context, *GetBlockedUsersRequest) (*GetBlockedUsersResponse, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -193,6 +219,12 @@ func (UnimplementedUserServiceServer) FindMatchingUsers(context.Context, *FindMa
 func (UnimplementedUserServiceServer) FindBySkillsToShare(context.Context, *FindBySkillsToShareRequest) (*ListUsersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FindBySkillsToShare not implemented")
 }
+func (UnimplementedUserServiceServer) UpdateLastActiveAt(context.Context, *UpdateLastActiveAtRequest) (*UpdateLastActiveAtResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateLastActiveAt not implemented")
+}
+func (UnimplementedUserServiceServer) GetBlockedUsers(context.Context, *GetBlockedUsersRequest) (*GetBlockedUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlockedUsers not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
 
@@ -376,6 +408,42 @@ func _UserService_FindBySkillsToShare_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_UpdateLastActiveAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateLastActiveAtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UpdateLastActiveAt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_UpdateLastActiveAt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UpdateLastActiveAt(ctx, req.(*UpdateLastActiveAtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetBlockedUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockedUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetBlockedUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetBlockedUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetBlockedUsers(ctx, req.(*GetBlockedUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -419,6 +487,14 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FindBySkillsToShare",
 			Handler:    _UserService_FindBySkillsToShare_Handler,
 		},
+		{
+			MethodName: "UpdateLastActiveAt",
+			Handler:    _UserService_UpdateLastActiveAt_Handler,
+		},
+		{
+			MethodName: "GetBlockedUsers",
+			Handler:    _UserService_GetBlockedUsers_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/user/user.proto",